@@ -5,6 +5,7 @@
 package create
 
 import (
+	"context"
 	"net/url"
 	"os"
 	"strings"
@@ -30,11 +31,11 @@ var CreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a scaffold for a project based on Go Templates",
 	Run: func(cmd *cobra.Command, args []string) {
-		Create()
+		Create(cmd.Context())
 	},
 }
 
-func Create() {
+func Create(ctx context.Context) {
 	fs := filesystem.NewFileSystem(afero.NewOsFs())
 	if VariablesFile != "" {
 		var vars interface{}
@@ -47,7 +48,7 @@ func Create() {
 			}
 		} else {
 			style.LogPrint("downloading variables file")
-			varsContent, err := shared.ReadFileFromURL(VariablesFile)
+			varsContent, err := shared.ReadFileFromURL(ctx, VariablesFile)
 			if err != nil {
 				style.ErrorPrint("reading file error: " + err.Error())
 				os.Exit(1)
@@ -67,7 +68,7 @@ func Create() {
 // build initializes the Builder and triggers the build process.
 // It reads the Kuma configuration file and applies templates to create the project structure.
 func build() {
-	fs := filesystem.NewFileSystem(afero.NewOsFs())
+	fs := shared.GetFileSystem()
 	// Initialize a new Builder with the provided configurations.
 	builder, err := domain.NewBuilder(fs, domain.NewConfig(ProjectPath, shared.KumaFilesPath))
 	builder.SetBuilderDataFromFile(shared.KumaFilesPath+"/"+FromFile, TemplateVariables)
@@ -81,6 +82,13 @@ func build() {
 		style.ErrorPrint(err.Error())
 		os.Exit(1)
 	}
+
+	if shared.DryRun {
+		if err = shared.PrintDryRunPlan(); err != nil {
+			style.ErrorPrint(err.Error())
+			os.Exit(1)
+		}
+	}
 }
 
 // init sets up flags for the 'create' subcommand and binds them to variables.
@@ -89,4 +97,5 @@ func init() {
 	CreateCmd.Flags().StringVarP(&VariablesFile, "variables", "v", "", "path or URL to the variables file")
 	CreateCmd.Flags().StringVarP(&ProjectPath, "project", "p", ".", "Path to the project you want to create")
 	CreateCmd.Flags().StringVarP(&FromFile, "from", "f", ".", "Path to the YAML file with the structure and templates")
+	CreateCmd.Flags().BoolVarP(&shared.DryRun, "dry-run", "", false, "Show which files would be created or changed without writing them")
 }
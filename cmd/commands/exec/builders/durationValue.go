@@ -0,0 +1,21 @@
+package execBuilders
+
+import (
+	"time"
+)
+
+// BuildDurationValue reads a duration such as "1m30s". Plain integers are
+// treated as seconds.
+func BuildDurationValue(key string, input map[string]interface{}, vars map[string]interface{}, required bool, component string) (time.Duration, error) {
+	if seconds, ok := input[key].(int); ok {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	valStr, err := BuildStringValue(key, input, vars, required, component)
+	if err != nil {
+		return 0, err
+	}
+	if valStr == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(valStr)
+}
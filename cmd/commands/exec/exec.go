@@ -1,8 +1,10 @@
 package exec
 
 import (
+	execList "github.com/arthurbcp/kuma/v2/cmd/commands/exec/list"
 	execModule "github.com/arthurbcp/kuma/v2/cmd/commands/exec/module"
 	execRun "github.com/arthurbcp/kuma/v2/cmd/commands/exec/run"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +16,25 @@ var ExecCmd = &cobra.Command{
 	},
 }
 
+// addRunFlags adds the flags that change how a run is executed to cmd. They
+// are not persistent flags of exec because they mean nothing to list.
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&shared.SetValues, "set", "", []string{}, "set a variable before the run starts (key=value, dotted keys allowed)")
+	cmd.Flags().StringVarP(&shared.AnswersFile, "answers", "", "", "JSON or YAML file with the variables to set before the run starts")
+	cmd.Flags().StringVarP(&shared.RecordFile, "record", "", "", "save every form answer to a YAML file")
+	cmd.Flags().StringVarP(&shared.ReplayFile, "replay", "", "", "answer the forms with the values of a recorded YAML file")
+	cmd.Flags().BoolVarP(&shared.NoInput, "no-input", "", false, "fail instead of prompting for missing values")
+	cmd.Flags().BoolVarP(&shared.Resume, "resume", "", false, "continue the last failed run from the step that failed")
+	cmd.Flags().BoolVarP(&shared.Transactional, "transaction", "", false, "restore every file changed by the run when it fails and allow kuma undo")
+	cmd.Flags().IntVarP(&shared.MaxRunDepth, "max-depth", "", shared.DefaultMaxRunDepth, "maximum number of runs nested inside each other, 0 for no limit")
+	cmd.Flags().DurationVarP(&shared.Timeout, "timeout", "", 0, "stop the run when it takes longer than this, e.g. 10m")
+	cmd.Flags().BoolVarP(&shared.DryRun, "dry-run", "", false, "show which files and commands a run would touch without changing anything")
+}
+
 func init() {
+	addRunFlags(execRun.ExecCmd)
+	addRunFlags(execModule.ExecModuleCmd)
 	ExecCmd.AddCommand(execRun.ExecCmd)
 	ExecCmd.AddCommand(execModule.ExecModuleCmd)
+	ExecCmd.AddCommand(execList.ListCmd)
 }
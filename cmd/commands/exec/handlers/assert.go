@@ -0,0 +1,59 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+)
+
+// HandleAssert fails the run with the step message when its condition is
+// false.
+func HandleAssert(params map[string]interface{}, vars map[string]interface{}) error {
+	isTrue, err := buildCondition(params, vars)
+	if err != nil {
+		return err
+	}
+	if isTrue {
+		return nil
+	}
+
+	message, err := execBuilders.BuildStringValue("message", params, vars, false, constants.AssertHandler)
+	if err != nil {
+		return fmt.Errorf("parsing message error: %s", err.Error())
+	}
+	if message == "" {
+		message = fmt.Sprintf("assertion failed: %v", params["condition"])
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// buildCondition evaluates the condition of the step, reporting template
+// errors and values that are not booleans instead of treating them as false.
+func buildCondition(params map[string]interface{}, vars map[string]interface{}) (bool, error) {
+	if isTrue, ok := params["condition"].(bool); ok {
+		return isTrue, nil
+	}
+	condition, err := execBuilders.BuildStringValue("condition", params, vars, true, constants.AssertHandler)
+	if err != nil {
+		return false, fmt.Errorf("parsing condition error: %s", err.Error())
+	}
+	isTrue, err := strconv.ParseBool(strings.TrimSpace(condition))
+	if err != nil {
+		return false, fmt.Errorf("condition must be true or false, got %q", condition)
+	}
+	return isTrue, nil
+}
+
+func init() {
+	Register(constants.AssertHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.AssertHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, HandleAssert(params, vars)
+	}))
+}
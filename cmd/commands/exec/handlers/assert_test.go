@@ -0,0 +1,56 @@
+package execHandlers
+
+import (
+	"testing"
+)
+
+func TestHandleAssert(t *testing.T) {
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{"name": "Users Service"},
+	}
+	testCases := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "True condition",
+			params: map[string]interface{}{"condition": `{{ regexMatch "^[A-Z]" .data.name }}`, "message": "unused"},
+		},
+		{
+			name:    "False condition with message",
+			params:  map[string]interface{}{"condition": `{{ regexMatch "^[a-z-]+$" .data.name }}`, "message": "{{ .data.name }} must be kebab-case"},
+			wantErr: "Users Service must be kebab-case",
+		},
+		{
+			name:    "False condition without message",
+			params:  map[string]interface{}{"condition": false},
+			wantErr: "assertion failed: false",
+		},
+		{
+			name:    "Condition that is not a bool",
+			params:  map[string]interface{}{"condition": "{{ .data.name }}"},
+			wantErr: `condition must be true or false, got "Users Service"`,
+		},
+		{
+			name:    "Missing condition",
+			params:  map[string]interface{}{},
+			wantErr: "parsing condition error: condition is required for assert",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := HandleAssert(tc.params, vars)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("HandleAssert() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("HandleAssert() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
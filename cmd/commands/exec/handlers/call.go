@@ -0,0 +1,94 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/internal/functions"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+)
+
+// variableRef matches values made of a single variable, such as
+// "{{ .data.api }}", which are passed as they are instead of as text.
+var variableRef = regexp.MustCompile(`^\{\{\s*\.([A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)*)\s*\}\}$`)
+
+// HandleRunCall executes a nested run in its own scope: the run starts with a
+// .data made of the with values, and only its outputs are copied back to the
+// .data of the caller. The outputs are also returned as the result of the call.
+func HandleRunCall(ctx context.Context, module string, params map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
+	name, err := execBuilders.BuildStringValue("name", params, vars, true, constants.RunHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{}
+	if with, ok := params["with"]; ok {
+		withMap, ok := with.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("with must be a map")
+		}
+		for key, value := range withMap {
+			data[key], err = buildWithValue(value, vars)
+			if err != nil {
+				return nil, fmt.Errorf("parsing with %s error: %s", key, err.Error())
+			}
+		}
+	}
+
+	outputs := []string{}
+	if o, ok := params["outputs"]; ok {
+		list, ok := o.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("outputs must be a list of variable names")
+		}
+		for _, output := range list {
+			str, ok := output.(string)
+			if !ok {
+				return nil, fmt.Errorf("outputs must be a list of variable names")
+			}
+			outputs = append(outputs, str)
+		}
+	}
+
+	scope := map[string]interface{}{"data": data}
+	if err := HandleRun(ctx, name, module, scope); err != nil {
+		// the scope of the call is lost, so a resumed run must execute it again
+		forgetSteps(currentStepPath())
+		return nil, err
+	}
+
+	callerData := vars["data"].(map[string]interface{})
+	result := map[string]interface{}{}
+	for _, output := range outputs {
+		value, ok := data[output]
+		if !ok {
+			return nil, fmt.Errorf("run %s did not set the output %s", name, output)
+		}
+		callerData[output] = value
+		result[output] = value
+	}
+	return result, nil
+}
+
+// buildWithValue replaces the variables of a with value. A string made of a
+// single variable passes a copy of the variable itself, so that maps and
+// lists can be given to the called run, and fails when the variable is not
+// set.
+func buildWithValue(value interface{}, vars map[string]interface{}) (interface{}, error) {
+	if str, ok := value.(string); ok {
+		if match := variableRef.FindStringSubmatch(str); match != nil {
+			found, ok := helpers.GetByPath(vars, match[1])
+			if !ok {
+				return nil, fmt.Errorf("variable not found: %s", match[1])
+			}
+			if foundMap, ok := found.(map[string]interface{}); ok {
+				return helpers.CopyMap(foundMap), nil
+			}
+			return found, nil
+		}
+	}
+	return helpers.ReplaceVarsInValue(value, vars, functions.GetFuncMap())
+}
@@ -1,28 +1,200 @@
 package execHandlers
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
 	"github.com/arthurbcp/kuma/v2/cmd/shared"
 	"github.com/arthurbcp/kuma/v2/internal/functions"
 	"github.com/arthurbcp/kuma/v2/internal/helpers"
 	"github.com/arthurbcp/kuma/v2/pkg/style"
 )
 
-func HandleCommand(cmdStr string, vars map[string]interface{}) error {
+// command is a cmd step ready to be executed.
+type command struct {
+	args    []string
+	display string
+	env     []string
+	dir     string
+	timeout time.Duration
+
+	// out, stderrOut and exitCodeOut are the .data variables that receive the
+	// trimmed standard output, the trimmed standard error and the exit code.
+	out         string
+	stderrOut   string
+	exitCodeOut string
+}
+
+// HandleCommand executes a cmd step. The value is the command line, an argv
+// list, or a map with the command and its options. The result holds the
+// trimmed stdout and stderr and the exit code of the command.
+func HandleCommand(ctx context.Context, value interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		params = map[string]interface{}{"command": value}
+	}
+	cmd, err := buildCommand(params, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if shared.DryRun {
+		shared.PlannedCommands = append(shared.PlannedCommands, cmd.display)
+		style.LogPrint(fmt.Sprintf("skipping (dry run): %s", cmd.display))
+		return skippedResult(), nil
+	}
+	style.LogPrint(fmt.Sprintf("running: %s", cmd.display))
+	return runCommand(ctx, cmd, vars["data"].(map[string]interface{}))
+}
+
+func buildCommand(params map[string]interface{}, vars map[string]interface{}) (command, error) {
 	var err error
+	cmd := command{}
 
-	cmdStr, err = helpers.ReplaceVars(cmdStr, vars, functions.GetFuncMap())
+	shell, err := execBuilders.BuildBoolValue("shell", params, vars, false, constants.CmdHandler)
+	if err != nil {
+		return cmd, err
+	}
+	switch line := params["command"].(type) {
+	case string:
+		line, err = helpers.ReplaceVars(line, vars, functions.GetFuncMap())
+		if err != nil {
+			return cmd, fmt.Errorf("parsing command error: %s", err.Error())
+		}
+		cmd.display = line
+		if shell {
+			cmd.args = []string{"sh", "-c", line}
+		} else if cmd.args, err = helpers.SplitShellWords(line); err != nil {
+			return cmd, fmt.Errorf("parsing command error: %s", err.Error())
+		}
+	case []interface{}:
+		if shell {
+			return cmd, fmt.Errorf("command must be a string when shell is true")
+		}
+		for _, arg := range line {
+			str, err := helpers.ReplaceVars(fmt.Sprint(arg), vars, functions.GetFuncMap())
+			if err != nil {
+				return cmd, fmt.Errorf("parsing command error: %s", err.Error())
+			}
+			cmd.args = append(cmd.args, str)
+		}
+		cmd.display = strings.Join(cmd.args, " ")
+	case nil:
+		return cmd, fmt.Errorf("command is required for %s", constants.CmdHandler)
+	default:
+		return cmd, fmt.Errorf("command must be a string or a list of arguments")
+	}
+	if len(cmd.args) == 0 {
+		return cmd, fmt.Errorf("command is empty")
+	}
+
+	if env, ok := params["env"]; ok {
+		envMap, ok := env.(map[string]interface{})
+		if !ok {
+			return cmd, fmt.Errorf("env must be a map")
+		}
+		for key, value := range envMap {
+			str, err := helpers.ReplaceVars(fmt.Sprint(value), vars, functions.GetFuncMap())
+			if err != nil {
+				return cmd, fmt.Errorf("parsing env %s error: %s", key, err.Error())
+			}
+			cmd.env = append(cmd.env, key+"="+str)
+		}
+		sort.Strings(cmd.env)
+	}
+
+	cmd.dir, err = execBuilders.BuildStringValue("dir", params, vars, false, constants.CmdHandler)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.timeout, err = execBuilders.BuildDurationValue("timeout", params, vars, false, constants.CmdHandler)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.out, err = execBuilders.BuildStringValue("out", params, vars, false, constants.CmdHandler)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.stderrOut, err = execBuilders.BuildStringValue("stderr-out", params, vars, false, constants.CmdHandler)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.exitCodeOut, err = execBuilders.BuildStringValue("exit-code-out", params, vars, false, constants.CmdHandler)
 	if err != nil {
-		return fmt.Errorf("parsing command error: %s", err.Error())
+		return cmd, err
+	}
+	return cmd, nil
+}
+
+// runCommand executes the command, streaming its output to the terminal while
+// capturing it for the step result and the outs stored in data. A non-zero exit
+// code only fails the step when it is not stored with exit-code-out. The
+// command is killed when ctx is canceled.
+func runCommand(ctx context.Context, cmd command, data map[string]interface{}) (map[string]interface{}, error) {
+	cmdCtx := ctx
+	if cmd.timeout > 0 {
+		var cancel context.CancelFunc
+		cmdCtx, cancel = context.WithTimeout(ctx, cmd.timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	execCmd := exec.CommandContext(cmdCtx, cmd.args[0], cmd.args[1:]...)
+	execCmd.Dir = cmd.dir
+	execCmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+	execCmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	if len(cmd.env) > 0 {
+		execCmd.Env = append(os.Environ(), cmd.env...)
 	}
+	err := execCmd.Run()
 
-	style.LogPrint(fmt.Sprintf("running: %s", cmdStr))
+	if ctx.Err() != nil {
+		return nil, stopError(ctx)
+	}
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("command timed out after %s", cmd.timeout)
+	}
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, fmt.Errorf("command error: %s", err.Error())
+	}
 
-	cmdArgs := strings.Split(cmdStr, " ")
-	if err := shared.RunCommand(cmdArgs[0], cmdArgs[1:]...); err != nil {
-		return fmt.Errorf("command error: %s", err.Error())
+	result := map[string]interface{}{
+		"stdout":   strings.TrimSpace(stdout.String()),
+		"stderr":   strings.TrimSpace(stderr.String()),
+		"exitCode": exitCode,
+	}
+	if cmd.out != "" {
+		data[cmd.out] = result["stdout"]
+	}
+	if cmd.stderrOut != "" {
+		data[cmd.stderrOut] = result["stderr"]
 	}
-	return nil
+	if cmd.exitCodeOut != "" {
+		data[cmd.exitCodeOut] = exitCode
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("command error: %s", err.Error())
+	}
+	return result, nil
+}
+
+func init() {
+	Register(constants.CmdHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		return HandleCommand(ctx, value, vars)
+	}))
 }
@@ -0,0 +1,71 @@
+package execHandlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHandleCommand(t *testing.T) {
+	dir := t.TempDir()
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{"message": "initial commit"},
+	}
+	steps := []interface{}{
+		map[string]interface{}{"cmd": map[string]interface{}{
+			"command": `sh -c 'printf "%s|%s" "$0" "$GREETING"' "{{.data.message}}"`,
+			"env":     map[string]interface{}{"GREETING": "hello {{.data.message}}"},
+			"out":     "printed",
+		}},
+		map[string]interface{}{"cmd": map[string]interface{}{
+			"command":       "pwd; echo oops >&2; exit 3",
+			"shell":         true,
+			"dir":           dir,
+			"out":           "cwd",
+			"stderr-out":    "stderr",
+			"exit-code-out": "code",
+		}},
+		map[string]interface{}{"cmd": []interface{}{"sh", "-c", "echo {{.data.code}}"}},
+	}
+	if err := HandleSteps(context.Background(), steps, "", vars); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+
+	data := vars["data"].(map[string]interface{})
+	if got := data["printed"]; got != "initial commit|hello initial commit" {
+		t.Errorf("printed = %q", got)
+	}
+	if got := data["cwd"].(string); !strings.HasSuffix(got, dir) {
+		t.Errorf("cwd = %q, want %q", got, dir)
+	}
+	if got := data["stderr"]; got != "oops" {
+		t.Errorf("stderr = %q, want %q", got, "oops")
+	}
+	if got := data["code"]; got != 3 {
+		t.Errorf("code = %v, want 3", got)
+	}
+}
+
+func TestHandleCommand_Errors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "Exit code", value: "false", want: "exit status 1"},
+		{name: "Pipe without shell", value: "echo a | cat", want: "set shell: true"},
+		{name: "Unterminated quote", value: `echo "a`, want: "unterminated double quote"},
+		{name: "Timeout", value: map[string]interface{}{"command": "sleep 5", "timeout": "50ms"}, want: "timed out after 50ms"},
+		{name: "Shell with argv", value: map[string]interface{}{"command": []interface{}{"ls"}, "shell": true}, want: "must be a string"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars := map[string]interface{}{"data": map[string]interface{}{}}
+			_, err := HandleCommand(context.Background(), tc.value, vars)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("HandleCommand() error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package execHandlers
 
 import (
+	"context"
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
 	"github.com/arthurbcp/kuma/v2/cmd/shared"
@@ -10,27 +11,53 @@ import (
 	"github.com/spf13/afero"
 )
 
-func HandleCreate(module string, data map[string]interface{}, vars map[string]interface{}) error {
+// HandleCreate builds the structure of a builder file and returns the files
+// it created.
+func HandleCreate(module string, data map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
 	path := shared.KumaFilesPath
-	fs := filesystem.NewFileSystem(afero.NewOsFs())
+	fs := &recordingFileSystem{FileSystemInterface: shared.GetFileSystem(), files: []interface{}{}}
 	if module != "" {
 		path = shared.KumaFilesPath + "/" + module + "/" + shared.KumaFilesPath
 	}
 	builder, err := domain.NewBuilder(fs, domain.NewConfig(".", path))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	from, err := execBuilders.BuildStringValue("from", data, vars, true, constants.CreateHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = builder.SetBuilderDataFromFile(path+"/"+from, vars)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err = handlers.NewBuilderHandler(builder).Build(); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return map[string]interface{}{"files": fs.files}, nil
+}
+
+// recordingFileSystem records the files created through it.
+type recordingFileSystem struct {
+	filesystem.FileSystemInterface
+	files []interface{}
+}
+
+func (r *recordingFileSystem) CreateFile(filename string) (afero.File, error) {
+	file, err := r.FileSystemInterface.CreateFile(filename)
+	if err == nil {
+		r.files = append(r.files, filename)
+	}
+	return file, err
+}
+
+func init() {
+	Register(constants.CreateHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.CreateHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleCreate(module, params, vars)
+	}))
 }
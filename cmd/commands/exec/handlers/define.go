@@ -1,15 +1,17 @@
 package execHandlers
 
 import (
+	"context"
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
 )
 
-func HandleDefine(params map[string]interface{}, vars map[string]interface{}) error {
+// HandleDefine sets a variable and returns its value.
+func HandleDefine(params map[string]interface{}, vars map[string]interface{}) (interface{}, error) {
 	data := vars["data"].(map[string]interface{})
 	variable, err := execBuilders.BuildStringValue("variable", params, vars, true, constants.DefineHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var value any
 	value, err = execBuilders.BuildBoolValue("value", params, vars, true, constants.DefineHandler)
@@ -18,10 +20,20 @@ func HandleDefine(params map[string]interface{}, vars map[string]interface{}) er
 		if err != nil {
 			value, err = execBuilders.BuildStringValue("value", params, vars, true, constants.DefineHandler)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 	data[variable] = value
-	return nil
+	return value, nil
+}
+
+func init() {
+	Register(constants.DefineHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.DefineHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleDefine(params, vars)
+	}))
 }
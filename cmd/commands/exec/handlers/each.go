@@ -0,0 +1,117 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+)
+
+// loopVars are the variables exposed to the steps of each iteration.
+var loopVars = []string{"item", "key", "index"}
+
+func HandleEach(ctx context.Context, module string, params map[string]interface{}, vars map[string]interface{}) error {
+	items, err := buildEachItems(params, vars)
+	if err != nil {
+		return err
+	}
+	run, err := execBuilders.BuildStringValue("run", params, vars, false, constants.EachHandler)
+	if err != nil {
+		return err
+	}
+	steps, hasSteps := params["steps"].([]interface{})
+	if run == "" && !hasSteps {
+		return fmt.Errorf("run or steps is required for %s", constants.EachHandler)
+	}
+	if run != "" && hasSteps {
+		return fmt.Errorf("run and steps are mutually exclusive for %s", constants.EachHandler)
+	}
+
+	// keep the loop variables of an outer loop to restore them afterwards
+	previous := map[string]interface{}{}
+	for _, name := range loopVars {
+		if value, ok := vars[name]; ok {
+			previous[name] = value
+		}
+	}
+	defer func() {
+		for _, name := range loopVars {
+			delete(vars, name)
+			if value, ok := previous[name]; ok {
+				vars[name] = value
+			}
+		}
+	}()
+
+	items = reflect.Indirect(items)
+	switch items.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < items.Len(); i++ {
+			err := handleEachItem(ctx, module, run, steps, i, i, items.Index(i).Interface(), vars)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := items.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for i, key := range keys {
+			err := handleEachItem(ctx, module, run, steps, i, key.Interface(), items.MapIndex(key).Interface(), vars)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("items must be a list or a map, got %s", items.Kind())
+	}
+	return nil
+}
+
+// buildEachItems resolves the items of the loop, given inline or as a dotted
+// path to a variable inside .data.
+func buildEachItems(params map[string]interface{}, vars map[string]interface{}) (reflect.Value, error) {
+	items, ok := params["items"]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("items is required for %s", constants.EachHandler)
+	}
+	if _, ok := items.(string); ok {
+		path, err := execBuilders.BuildStringValue("items", params, vars, true, constants.EachHandler)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		items, ok = helpers.GetByPath(vars["data"].(map[string]interface{}), path)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("variable not found: %s", path)
+		}
+	}
+	if items == nil {
+		return reflect.ValueOf([]interface{}{}), nil
+	}
+	return reflect.ValueOf(items), nil
+}
+
+func handleEachItem(ctx context.Context, module string, run string, steps []interface{}, index int, key interface{}, item interface{}, vars map[string]interface{}) error {
+	vars["index"] = index
+	vars["key"] = key
+	vars["item"] = item
+	if err := handleRunOrSteps(ctx, fmt.Sprintf("item-%d", index), run, steps, module, vars); err != nil {
+		return fmt.Errorf("[item: %v] - %w", key, err)
+	}
+	return nil
+}
+
+func init() {
+	Register(constants.EachHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.EachHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, HandleEach(ctx, module, params, vars)
+	}))
+}
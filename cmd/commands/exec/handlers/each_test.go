@@ -0,0 +1,63 @@
+package execHandlers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHandleEach(t *testing.T) {
+	steps := []interface{}{
+		map[string]interface{}{"define": map[string]interface{}{
+			"variable": "trail",
+			"value":    "{{ .data.trail }}{{ .index }}:{{ .key }}={{ .item }};",
+		}},
+	}
+	testCases := []struct {
+		name  string
+		items interface{}
+		want  string
+	}{
+		{name: "List", items: []interface{}{"a", "b", "c"}, want: "0:0=a;1:1=b;2:2=c;"},
+		{name: "Map sorted by key", items: map[string]interface{}{"zeta": 1, "alpha": 2, "mid": 3}, want: "0:alpha=2;1:mid=3;2:zeta=1;"},
+		{name: "Path to a variable", items: "nested.list", want: "0:0=x;1:1=y;"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars := map[string]interface{}{
+				"data": map[string]interface{}{
+					"trail":  "",
+					"nested": map[string]interface{}{"list": []interface{}{"x", "y"}},
+				},
+				"item":  "outer-item",
+				"index": 7,
+			}
+			params := map[string]interface{}{"items": tc.items, "steps": steps}
+			if err := HandleEach(context.Background(), "", params, vars); err != nil {
+				t.Fatalf("HandleEach() error = %v", err)
+			}
+			if got := vars["data"].(map[string]interface{})["trail"]; got != tc.want {
+				t.Errorf("trail = %q, want %q", got, tc.want)
+			}
+			if vars["item"] != "outer-item" || vars["index"] != 7 {
+				t.Errorf("item = %v, index = %v, want the outer values restored", vars["item"], vars["index"])
+			}
+			if _, ok := vars["key"]; ok {
+				t.Errorf("key = %v, want it removed after the loop", vars["key"])
+			}
+		})
+	}
+
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	err := HandleEach(context.Background(), "", map[string]interface{}{"items": 3, "steps": steps}, vars)
+	if err == nil || err.Error() != "items must be a list or a map, got int" {
+		t.Errorf("HandleEach() error = %v, want items must be a list or a map", err)
+	}
+	err = HandleEach(context.Background(), "", map[string]interface{}{"items": []interface{}{"a"}, "run": "setup", "steps": steps}, vars)
+	if err == nil || err.Error() != "run and steps are mutually exclusive for each" {
+		t.Errorf("HandleEach() error = %v, want run and steps mutually exclusive", err)
+	}
+	if _, ok := vars["data"].(map[string]interface{})["trail"]; ok {
+		t.Errorf("the steps were executed although run was set")
+	}
+}
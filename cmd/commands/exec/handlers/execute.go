@@ -0,0 +1,97 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+
+	execFormHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers/form"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+)
+
+// ExecuteRun executes a run started from the command line. It builds the
+// initial variables from the command flags, executes the run and reports
+// what happened according to the execution mode.
+//
+// With --resume, the run, its variables and its progress are restored from
+// the journal of the last failed run, and the --set values are applied on top.
+//
+// With --transaction, the files changed by a failed run are restored, and the
+// original files of a successful run are saved so that `kuma undo` can revert it.
+//
+// The answers of the form fields marked with remember are kept by run, and
+// pre-fill the same fields the next time the run is executed.
+//
+// When ctx is canceled, by Ctrl+C or a timeout, the running step is stopped
+// and the run fails, keeping its journal and rolling back its transaction.
+func ExecuteRun(ctx context.Context, name, moduleName string) error {
+	data, err := shared.BuildData()
+	if err != nil {
+		return err
+	}
+	if err := execFormHandlers.LoadReplay(); err != nil {
+		return err
+	}
+	vars := map[string]interface{}{
+		"data": data,
+	}
+
+	j := &Journal{Run: name, Module: moduleName}
+	if shared.Resume {
+		j, err = LoadJournal()
+		if err != nil {
+			return err
+		}
+		if name != "" && (name != j.Run || moduleName != j.Module) {
+			return fmt.Errorf("the last failed run is %s, not %s", j.Run, name)
+		}
+		name, moduleName = j.Run, j.Module
+		vars = j.Vars
+		resumedData := vars["data"].(map[string]interface{})
+		for key, value := range data {
+			resumedData[key] = value
+		}
+		style.LogPrint(fmt.Sprintf("resuming run %s after %d completed steps", name, len(j.Completed)))
+	}
+	if err := execFormHandlers.LoadMemory(runRef(name, moduleName)); err != nil {
+		return err
+	}
+	if !shared.DryRun {
+		startJournal(j, vars)
+	}
+
+	runErr := HandleRun(ctx, name, moduleName, vars)
+	if tx := shared.GetTransaction(); tx != nil {
+		if runErr != nil {
+			if err := shared.RestoreSnapshot(tx.Snapshot()); err != nil {
+				runErr = fmt.Errorf("%w\nrolling back error: %s", runErr, err.Error())
+			} else {
+				style.LogPrint("run failed, every file it changed was restored")
+			}
+			// the restored files make the completed steps worthless
+			discardJournal()
+		} else if err := shared.SaveUndo(tx.Snapshot()); err != nil {
+			style.ErrorPrint(err.Error())
+		}
+	}
+	if err := finishJournal(runErr); err != nil {
+		style.ErrorPrint(err.Error())
+	}
+	if err := execFormHandlers.SaveMemory(); err != nil {
+		style.ErrorPrint(err.Error())
+	}
+	if err := execFormHandlers.SaveRecord(); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("%w\n%s", runErr, err.Error())
+		}
+		return err
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	if shared.DryRun {
+		return shared.PrintDryRunPlan()
+	}
+	return nil
+}
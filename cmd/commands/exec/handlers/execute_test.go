@@ -0,0 +1,179 @@
+package execHandlers
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+// readTree returns the content of every file under the working directory,
+// leaving out the skipped directories.
+func readTree(t *testing.T, skip ...string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if slices.Contains(skip, path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		files[path] = string(content)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("reading the project error: %v", err)
+	}
+	return files
+}
+
+func TestExecuteRun_DryRun(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	shared.DryRun = true
+	shared.PlannedCommands = nil
+	shared.SetFileSystem(nil)
+	defer func() {
+		shared.DryRun = false
+		shared.PlannedCommands = nil
+		shared.SetFileSystem(nil)
+	}()
+
+	files := map[string]string{
+		shared.KumaRunsPath + "/setup.yaml": `
+setup:
+  steps:
+    - load: {from: vars.yaml, out: service}
+    - create: {from: service.yaml}
+    - modify: {file: main.go, template: templates/route.tmpl, mark: "// routes"}
+    - file: {action: delete, path: "logs/*.log"}
+    - file: {action: delete, path: "logs/*.log"}
+      register: again
+    - cmd: go mod tidy
+    - cmd: git commit -m "add {{ .data.service.name }}, {{ len .data.again }} logs left"
+`,
+		shared.KumaFilesPath + "/service.yaml": `
+structure:
+  services:
+    "{{ .data.service.name }}.go":
+      template: templates/service.tmpl
+      data:
+        name: "{{ .data.service.name }}"
+`,
+		shared.KumaFilesPath + "/templates/service.tmpl": "package {{ .data.name }}\n",
+		shared.KumaFilesPath + "/templates/route.tmpl":   "// routes\nroute(\"/{{ .data.service.name }}\")\n",
+		"vars.yaml":     "name: users\n",
+		"main.go":       "package main\n// routes\n",
+		"logs/app.log":  "log\n",
+		"logs/keep.txt": "keep\n",
+	}
+	for path, content := range files {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	before := readTree(t)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	printed := make(chan string)
+	go func() {
+		output, _ := io.ReadAll(reader)
+		printed <- string(output)
+	}()
+	stdout := os.Stdout
+	os.Stdout = writer
+	err = ExecuteRun(context.Background(), "setup", "")
+	os.Stdout = stdout
+	writer.Close()
+	output := <-printed
+	if err != nil {
+		t.Fatalf("ExecuteRun() error = %v", err)
+	}
+
+	if after := readTree(t); !reflect.DeepEqual(after, before) {
+		t.Errorf("the dry run changed the project:\n%v\nwant\n%v", after, before)
+	}
+	wantCommands := []string{"go mod tidy", `git commit -m "add users, 0 logs left"`}
+	if !reflect.DeepEqual(shared.PlannedCommands, wantCommands) {
+		t.Errorf("PlannedCommands = %q, want %q", shared.PlannedCommands, wantCommands)
+	}
+	for _, want := range []string{"+ services/users.go", "~ main.go", "- logs/app.log", "$ go mod tidy", "$ git commit"} {
+		if strings.Count(output, want) != 1 {
+			t.Errorf("the plan lists %q %d times, want once:\n%s", want, strings.Count(output, want), output)
+		}
+	}
+}
+
+// writeProject writes the files of a project in a new git repository, as
+// transactional runs stage the files they write.
+func writeProject(t *testing.T, files map[string]string) {
+	t.Helper()
+	if err := exec.Command("git", "init", "--quiet").Run(); err != nil {
+		t.Fatalf("git init error: %v", err)
+	}
+	for path, content := range files {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+}
+
+func TestExecuteRun_TransactionRollback(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	shared.Transactional = true
+	shared.SetFileSystem(nil)
+	defer func() {
+		shared.Transactional = false
+		shared.SetFileSystem(nil)
+	}()
+
+	writeProject(t, map[string]string{
+		shared.KumaRunsPath + "/setup.yaml": `
+setup:
+  steps:
+    - modify: {file: main.go, template: route.tmpl, mark: "// routes"}
+    - file: {action: copy, from: main.go, to: cmd/main.go}
+    - file: {action: delete, path: "logs/*.log"}
+    - cmd: "false"
+    - log: never
+`,
+		shared.KumaFilesPath + "/route.tmpl": "// routes\nroute()\n",
+		"main.go":                            "package main\n// routes\n",
+		"logs/app.log":                       "log\n",
+	})
+	before := readTree(t, ".git", shared.KumaStatePath)
+
+	err := ExecuteRun(context.Background(), "setup", "")
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+		t.Fatalf("ExecuteRun() error = %v, want the cmd step to fail", err)
+	}
+	if after := readTree(t, ".git", shared.KumaStatePath); !reflect.DeepEqual(after, before) {
+		t.Errorf("the failed run changed the project:\n%v\nwant\n%v", after, before)
+	}
+	if _, err := os.Stat("cmd"); !os.IsNotExist(err) {
+		t.Errorf("the directory created by the failed run was kept")
+	}
+	if _, err := shared.LoadUndo(); err == nil {
+		t.Errorf("LoadUndo() error = nil, want no undo record for a failed run")
+	}
+}
@@ -0,0 +1,194 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"github.com/spf13/afero"
+)
+
+// File operations supported by the file handler.
+const (
+	CopyFileAction   = "copy"
+	MoveFileAction   = "move"
+	DeleteFileAction = "delete"
+	MkdirFileAction  = "mkdir"
+	ChmodFileAction  = "chmod"
+)
+
+// HandleFile copies, moves, deletes or changes the permissions of the files
+// matching a path or glob, or creates a directory. It returns the paths it
+// affected: the targets of a copy or move, and the matched paths otherwise.
+//
+// Copy, move and chmod fail when no file matches, since they can not do what
+// the step asks for. Delete succeeds without matches: the files are already
+// gone, so a run can remove files that may not exist.
+func HandleFile(params map[string]interface{}, vars map[string]interface{}) ([]interface{}, error) {
+	fs := shared.GetFileSystem()
+	action, err := execBuilders.BuildStringValue("action", params, vars, true, constants.FileHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case CopyFileAction, MoveFileAction:
+		from, err := execBuilders.BuildStringValue("from", params, vars, true, constants.FileHandler)
+		if err != nil {
+			return nil, err
+		}
+		to, err := execBuilders.BuildStringValue("to", params, vars, true, constants.FileHandler)
+		if err != nil {
+			return nil, err
+		}
+		return copyOrMove(fs, action, from, to)
+	case DeleteFileAction:
+		path, err := execBuilders.BuildStringValue("path", params, vars, true, constants.FileHandler)
+		if err != nil {
+			return nil, err
+		}
+		// unlike matchFiles, no match is not an error for delete
+		matches, err := fs.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %s", path, err.Error())
+		}
+		paths := []interface{}{}
+		for _, match := range matches {
+			if err := fs.DeleteFile(match); err != nil {
+				return paths, fmt.Errorf("deleting %s error: %s", match, err.Error())
+			}
+			style.CheckMarkPrint(fmt.Sprintf("%s deleted", match))
+			paths = append(paths, match)
+		}
+		return paths, nil
+	case MkdirFileAction:
+		path, err := execBuilders.BuildStringValue("path", params, vars, true, constants.FileHandler)
+		if err != nil {
+			return nil, err
+		}
+		if err := fs.CreateDirectoryIfNotExists(path); err != nil {
+			return nil, err
+		}
+		return []interface{}{path}, nil
+	case ChmodFileAction:
+		path, err := execBuilders.BuildStringValue("path", params, vars, true, constants.FileHandler)
+		if err != nil {
+			return nil, err
+		}
+		matches, err := matchFiles(fs, path)
+		if err != nil {
+			return nil, err
+		}
+		paths := []interface{}{}
+		for _, match := range matches {
+			info, err := fs.GetAferoFs().Stat(match)
+			if err != nil {
+				return paths, err
+			}
+			mode, err := buildFileMode(params["mode"], info.Mode().Perm())
+			if err != nil {
+				return paths, err
+			}
+			if err := fs.Chmod(match, mode); err != nil {
+				return paths, fmt.Errorf("changing the mode of %s error: %s", match, err.Error())
+			}
+			style.CheckMarkPrint(fmt.Sprintf("%s mode set to %04o", match, mode))
+			paths = append(paths, match)
+		}
+		return paths, nil
+	}
+	return nil, fmt.Errorf("invalid action %s, expected one of %s, %s, %s, %s or %s", action,
+		CopyFileAction, MoveFileAction, DeleteFileAction, MkdirFileAction, ChmodFileAction)
+}
+
+// copyOrMove copies or moves the files matching from. When from matches more
+// than one file, or to ends with a slash or is an existing directory, the
+// files are placed inside to.
+func copyOrMove(fs filesystem.FileSystemInterface, action string, from string, to string) ([]interface{}, error) {
+	matches, err := matchFiles(fs, from)
+	if err != nil {
+		return nil, err
+	}
+	isDir, err := afero.IsDir(fs.GetAferoFs(), to)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	intoDir := len(matches) > 1 || isDir || strings.HasSuffix(to, "/")
+
+	paths := []interface{}{}
+	for _, match := range matches {
+		target := to
+		if intoDir {
+			target = filepath.Join(to, filepath.Base(match))
+		}
+		if action == CopyFileAction {
+			err = fs.CopyFile(match, target)
+		} else {
+			err = fs.MoveFile(match, target)
+		}
+		if err != nil {
+			return paths, fmt.Errorf("%s %s to %s error: %s", action, match, target, err.Error())
+		}
+		if action == CopyFileAction {
+			style.CheckMarkPrint(fmt.Sprintf("%s copied to %s", match, target))
+		} else {
+			style.CheckMarkPrint(fmt.Sprintf("%s moved to %s", match, target))
+		}
+		paths = append(paths, target)
+	}
+	return paths, nil
+}
+
+// matchFiles returns the files matching a path or glob, failing when there
+// is none.
+func matchFiles(fs filesystem.FileSystemInterface, pattern string) ([]string, error) {
+	matches, err := fs.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %s: %s", pattern, err.Error())
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	return matches, nil
+}
+
+// buildFileMode reads a mode given as an octal string ("0755"), a YAML octal
+// number (0755) or "+x", which makes the file executable by whoever can read
+// it.
+func buildFileMode(value interface{}, current os.FileMode) (os.FileMode, error) {
+	switch mode := value.(type) {
+	case int:
+		return os.FileMode(mode), nil
+	case string:
+		mode = strings.TrimSpace(mode)
+		if mode == "+x" {
+			return current | (current&0444)>>2, nil
+		}
+		parsed, err := strconv.ParseUint(strings.TrimPrefix(mode, "0o"), 8, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid mode %s", mode)
+		}
+		return os.FileMode(parsed), nil
+	case nil:
+		return 0, fmt.Errorf("mode is required for %s", ChmodFileAction)
+	}
+	return 0, fmt.Errorf("invalid mode %v", value)
+}
+
+func init() {
+	Register(constants.FileHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.FileHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleFile(params, vars)
+	}))
+}
@@ -0,0 +1,67 @@
+package execHandlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/spf13/afero"
+)
+
+func TestHandleFile(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, "assets/logo.png", []byte("logo"), 0644)
+	afero.WriteFile(memFs, "assets/icon.png", []byte("icon"), 0644)
+	afero.WriteFile(memFs, "service.go.tmpl", []byte("package users"), 0644)
+	afero.WriteFile(memFs, "placeholder/.keep", []byte(""), 0644)
+	afero.WriteFile(memFs, "run.sh", []byte("#!/bin/sh"), 0644)
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{"name": "users"},
+	}
+	steps := []interface{}{
+		map[string]interface{}{"file": map[string]interface{}{"action": "mkdir", "path": "public"}},
+		map[string]interface{}{"file": map[string]interface{}{"action": "copy", "from": "assets/*.png", "to": "public"}},
+		map[string]interface{}{"file": map[string]interface{}{"action": "move", "from": "service.go.tmpl", "to": "{{.data.name}}/service.go"}},
+		map[string]interface{}{"file": map[string]interface{}{"action": "delete", "path": "placeholder"}},
+		map[string]interface{}{"file": map[string]interface{}{"action": "delete", "path": "*.bak"}},
+		map[string]interface{}{"file": map[string]interface{}{"action": "chmod", "path": "*.sh", "mode": "+x"}},
+	}
+	if err := HandleSteps(context.Background(), steps, "", vars); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+
+	for path, want := range map[string]bool{
+		"public/logo.png":  true,
+		"public/icon.png":  true,
+		"users/service.go": true,
+		"service.go.tmpl":  false,
+		"placeholder":      false,
+	} {
+		if exists, _ := afero.Exists(memFs, path); exists != want {
+			t.Errorf("%s exists = %v, want %v", path, exists, want)
+		}
+	}
+	if info, _ := memFs.Stat("run.sh"); info.Mode().Perm() != 0755 {
+		t.Errorf("run.sh mode = %o, want 755", info.Mode().Perm())
+	}
+
+	errorCases := map[string]map[string]interface{}{
+		"no files match missing/*":   {"action": "copy", "from": "missing/*", "to": "public"},
+		"no files match *.py":        {"action": "chmod", "path": "*.py", "mode": "+x"},
+		"invalid action rename":      {"action": "rename", "from": "a", "to": "b"},
+		"invalid mode 9":             {"action": "chmod", "path": "run.sh", "mode": "9"},
+		"to is required for file":    {"action": "move", "from": "run.sh"},
+		"mode is required for chmod": {"action": "chmod", "path": "run.sh"},
+	}
+	for want, params := range errorCases {
+		_, err := HandleFile(params, vars)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("HandleFile(%v) error = %v, want it to contain %q", params, err, want)
+		}
+	}
+}
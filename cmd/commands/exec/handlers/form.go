@@ -0,0 +1,17 @@
+package execHandlers
+
+import (
+	"context"
+	execFormHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers/form"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+)
+
+func init() {
+	Register(constants.FormHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.FormHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return execFormHandlers.HandleForm(ctx, params, vars)
+	}))
+}
@@ -0,0 +1,60 @@
+package execFormHandlers
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// recorded holds every answer collected while --record is set.
+	recorded = map[string]interface{}{}
+
+	// replayed holds the answers loaded from the --replay file.
+	replayed = map[string]interface{}{}
+)
+
+// LoadReplay reads the answers of the --replay file, which are used instead
+// of prompting the form fields that write to them.
+func LoadReplay() error {
+	if shared.ReplayFile == "" {
+		return nil
+	}
+	answers, err := helpers.UnmarshalFile(shared.ReplayFile, shared.GetFileSystem())
+	if err != nil {
+		return fmt.Errorf("reading replay file error: %s", err.Error())
+	}
+	replayed = answers
+	return nil
+}
+
+// SaveRecord writes every answer collected by the forms to the --record file.
+// The file is written even in dry-run mode, since it is not part of the
+// project being generated.
+func SaveRecord() error {
+	if shared.RecordFile == "" {
+		return nil
+	}
+	content, err := yaml.Marshal(recorded)
+	if err != nil {
+		return fmt.Errorf("encoding recorded answers error: %s", err.Error())
+	}
+	if err := os.WriteFile(shared.RecordFile, content, 0644); err != nil {
+		return fmt.Errorf("writing record file error: %s", err.Error())
+	}
+	return nil
+}
+
+func recordAnswer(out string, value interface{}) {
+	if shared.RecordFile != "" {
+		recorded[out] = value
+	}
+}
+
+func replayAnswer(out string) (interface{}, bool) {
+	value, ok := replayed[out]
+	return value, ok
+}
@@ -0,0 +1,64 @@
+package execFormHandlers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answers.yaml")
+	shared.NoInput = true
+	defer func() {
+		shared.NoInput = false
+		shared.SetValues = nil
+		shared.RecordFile = ""
+		shared.ReplayFile = ""
+		recorded = map[string]interface{}{}
+		replayed = map[string]interface{}{}
+		shared.BuildData()
+	}()
+	form := map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"input": map[string]interface{}{"out": "team"}},
+		map[string]interface{}{"confirm": map[string]interface{}{"out": "deploy", "title": "Deploy?"}},
+		map[string]interface{}{"input": map[string]interface{}{"out": "name", "default": "users"}},
+	}}
+
+	// record a run answered with --set
+	shared.RecordFile = file
+	shared.SetValues = []string{"team=platform", "deploy=true"}
+	data, err := shared.BuildData()
+	if err != nil {
+		t.Fatalf("BuildData() error = %v", err)
+	}
+	want, err := HandleForm(context.Background(), form, map[string]interface{}{"data": data})
+	if err != nil {
+		t.Fatalf("HandleForm() error = %v", err)
+	}
+	if err := SaveRecord(); err != nil {
+		t.Fatalf("SaveRecord() error = %v", err)
+	}
+
+	// replay it without any preset value
+	shared.RecordFile = ""
+	shared.SetValues = nil
+	recorded = map[string]interface{}{}
+	data, _ = shared.BuildData()
+	shared.ReplayFile = file
+	if err := LoadReplay(); err != nil {
+		t.Fatalf("LoadReplay() error = %v", err)
+	}
+	got, err := HandleForm(context.Background(), form, map[string]interface{}{"data": data})
+	if err != nil {
+		t.Fatalf("HandleForm() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed answers = %v, want the recorded %v", got, want)
+	}
+	if data["team"] != "platform" || data["deploy"] != true {
+		t.Errorf("data = %v, want the replayed answers", data)
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 func HandleConfirm(input map[string]interface{}, vars map[string]interface{}) (*huh.Confirm, string, *bool, error) {
 	var err error
-	data := vars["data"].(map[string]interface{})
 
 	label, err := execBuilders.BuildStringValue("label", input, vars, false, constants.ConfirmComponent)
 	if err != nil {
@@ -38,7 +37,10 @@ func HandleConfirm(input map[string]interface{}, vars map[string]interface{}) (*
 		negative = "No"
 	}
 
-	var outValue bool
+	outValue, err := execBuilders.BuildBoolValue("default", input, vars, false, constants.ConfirmComponent)
+	if err != nil {
+		return nil, "", nil, err
+	}
 	h := huh.NewConfirm().
 		Title(label).
 		Description(description).
@@ -46,7 +48,5 @@ func HandleConfirm(input map[string]interface{}, vars map[string]interface{}) (*
 		Negative(negative).
 		Value(&outValue)
 
-	data[out] = out
-
 	return h, out, &outValue, nil
 }
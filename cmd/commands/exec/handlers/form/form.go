@@ -1,95 +1,152 @@
 package execFormHandlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
 
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
 	"github.com/arthurbcp/kuma/v2/pkg/style"
 	"github.com/charmbracelet/huh"
 )
 
-func HandleForm(formData map[string]interface{}, vars map[string]interface{}) error {
+// HandleForm asks the fields of a form, or takes their values from the preset
+// and replayed answers, and returns the answers by variable.
+func HandleForm(ctx context.Context, formData map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
 	data := vars["data"].(map[string]interface{})
+	answers := map[string]interface{}{}
 	huhFields := []huh.Field{}
 	title, err := execBuilders.BuildStringValue("title", formData, vars, false, constants.FormComponent)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	description, err := execBuilders.BuildStringValue("description", formData, vars, false, constants.FormComponent)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	accessibility, err := execBuilders.BuildBoolValue("accessibility", formData, vars, false, constants.FormComponent)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if _, ok := formData["fields"]; !ok {
-		return fmt.Errorf("fields is required")
+		return nil, fmt.Errorf("fields is required")
 	}
+	fields := []formField{}
 	for _, field := range formData["fields"].([]interface{}) {
 		fieldMap, ok := field.(map[string]interface{})
 		if !ok {
-			return fmt.Errorf("invalid field map")
+			return nil, fmt.Errorf("invalid field map")
 		}
 		for key, value := range fieldMap {
 			if value, ok := value.(map[string]interface{}); ok {
+				out, err := execBuilders.BuildStringValue("out", value, vars, true, key)
+				if err != nil {
+					return nil, fmt.Errorf("[field:%s] - %s", key, err.Error())
+				}
+				remember, err := execBuilders.BuildBoolValue("remember", value, vars, false, key)
+				if err != nil {
+					return nil, fmt.Errorf("[field:%s] - %s", key, err.Error())
+				}
+				if value, ok := shared.PresetValue(out); ok {
+					// the preset is read from the flags, as the .data of a
+					// run called with with does not hold it
+					helpers.SetByPath(data, out, value)
+					recordAnswer(out, value)
+					if remember {
+						rememberAnswer(out, value)
+					}
+					answers[out] = value
+					continue
+				}
+				if value, ok := replayAnswer(out); ok {
+					data[out] = value
+					recordAnswer(out, value)
+					if remember {
+						rememberAnswer(out, value)
+					}
+					answers[out] = value
+					continue
+				}
+				var huhField huh.Field
+				var outValue interface{}
 				switch key {
 				case constants.SelectComponent:
-					huhField, out, outValue, err := HandleSelect(value, vars)
-					if err != nil {
-						return fmt.Errorf("[field:%s] - %s", constants.SelectComponent, err.Error())
-					}
-					huhFields = append(huhFields, huhField)
-					data[out] = outValue
+					huhField, out, outValue, err = HandleSelect(value, vars)
 				case constants.InputComponent:
-					huhField, out, outValue, err := HandleInput(value, vars)
-					if err != nil {
-						return fmt.Errorf("[field:%s] - %s", constants.InputComponent, err.Error())
-					}
-					huhFields = append(huhFields, huhField)
-					data[out] = outValue
+					huhField, out, outValue, err = HandleInput(value, vars)
 				case constants.MultiSelectComponent:
-					huhField, out, outValue, err := HandleMultiSelect(value, vars)
-					if err != nil {
-						return fmt.Errorf("[field:%s] - %s", constants.MultiSelectComponent, err.Error())
-					}
-					huhFields = append(huhFields, huhField)
-					data[out] = outValue
+					huhField, out, outValue, err = HandleMultiSelect(value, vars)
 				case constants.TextComponent:
-					huhField, out, outValue, err := HandleText(value, vars)
-					if err != nil {
-						return fmt.Errorf("[field:%s] - %s", constants.TextComponent, err.Error())
-					}
-					huhFields = append(huhFields, huhField)
-					data[out] = outValue
+					huhField, out, outValue, err = HandleText(value, vars)
 				case constants.ConfirmComponent:
-					huhField, out, outValue, err := HandleConfirm(value, vars)
-					if err != nil {
-						return fmt.Errorf("[field:%s] - %s", constants.ConfirmComponent, err.Error())
-					}
-					huhFields = append(huhFields, huhField)
-					data[out] = outValue
+					huhField, out, outValue, err = HandleConfirm(value, vars)
 				default:
-					return fmt.Errorf("invalid field type: %s", key)
+					return nil, fmt.Errorf("invalid field type: %s", key)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("[field:%s] - %s", key, err.Error())
 				}
+				_, hasDefault := value["default"]
+				if remember {
+					// the last answer replaces the default of the field
+					if answer, ok := rememberedAnswer(out); ok && prefill(outValue, answer) {
+						hasDefault = true
+					}
+				}
+				if shared.NoInput && !hasDefault {
+					return nil, fmt.Errorf("[field:%s] - missing value for %s, set it with --set %s=<value> or --answers", key, out, out)
+				}
+				huhFields = append(huhFields, huhField)
+				fields = append(fields, formField{out: out, value: outValue, remember: remember})
 			} else {
-				return fmt.Errorf("invalid field type: %s", key)
+				return nil, fmt.Errorf("invalid field type: %s", key)
 			}
 		}
 	}
-	form := huh.NewForm(
-		huh.NewGroup(huhFields...).
-			Title(title).
-			Description(description),
-	)
-	form.WithTheme(style.KumaTheme())
-	form.WithAccessible(accessibility)
-	err = form.Run()
-	if err != nil {
-		return fmt.Errorf("error running form: %s", err.Error())
+	if len(huhFields) > 0 && !shared.NoInput {
+		form := huh.NewForm(
+			huh.NewGroup(huhFields...).
+				Title(title).
+				Description(description),
+		)
+		form.WithTheme(style.KumaTheme())
+		form.WithAccessible(accessibility)
+		err = form.RunWithContext(ctx)
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return nil, shared.ErrTimedOut
+		case ctx.Err() != nil || errors.Is(err, huh.ErrUserAborted):
+			// Ctrl+C inside a form interrupts the run like a signal
+			shared.Interrupt()
+			return nil, shared.ErrInterrupted
+		case err != nil:
+			return nil, fmt.Errorf("error running form: %s", err.Error())
+		}
 	}
 
-	return nil
+	for _, field := range fields {
+		data[field.out] = reflect.ValueOf(field.value).Elem().Interface()
+		recordAnswer(field.out, data[field.out])
+		if field.remember {
+			rememberAnswer(field.out, data[field.out])
+		}
+		answers[field.out] = data[field.out]
+	}
+	return answers, nil
+}
+
+// formField links the value bound to a huh field to its .data variable.
+type formField struct {
+	out string
+
+	// value is a pointer to the value edited by the huh field.
+	value interface{}
+
+	// remember saves the answer as the value of the field in the next runs.
+	remember bool
 }
@@ -0,0 +1,59 @@
+package execFormHandlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestHandleForm_Preset(t *testing.T) {
+	shared.NoInput = true
+	shared.SetValues = []string{"team=platform", "deploy=false"}
+	defer func() {
+		shared.NoInput = false
+		shared.SetValues = nil
+		shared.BuildData()
+	}()
+	data, err := shared.BuildData()
+	if err != nil {
+		t.Fatalf("BuildData() error = %v", err)
+	}
+	vars := map[string]interface{}{"data": data}
+
+	form := map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"input": map[string]interface{}{"out": "team"}},
+		map[string]interface{}{"confirm": map[string]interface{}{"out": "deploy", "title": "Deploy?"}},
+	}}
+	answers, err := HandleForm(context.Background(), form, vars)
+	if err != nil {
+		t.Fatalf("HandleForm() error = %v", err)
+	}
+	want := map[string]interface{}{"team": "platform", "deploy": false}
+	if !reflect.DeepEqual(answers, want) {
+		t.Errorf("answers = %v, want %v", answers, want)
+	}
+
+	// a run called with with starts from a .data without the preset values
+	scope := map[string]interface{}{"data": map[string]interface{}{"service": "api"}}
+	form = map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"input": map[string]interface{}{"out": "team"}},
+	}}
+	if _, err := HandleForm(context.Background(), form, scope); err != nil {
+		t.Fatalf("HandleForm() in a called run error = %v", err)
+	}
+	if got := scope["data"].(map[string]interface{})["team"]; got != "platform" {
+		t.Errorf("team in a called run = %v, want platform", got)
+	}
+
+	form = map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"input": map[string]interface{}{"out": "team"}},
+		map[string]interface{}{"input": map[string]interface{}{"out": "owner"}},
+	}}
+	_, err = HandleForm(context.Background(), form, vars)
+	wantErr := "[field:input] - missing value for owner, set it with --set owner=<value> or --answers"
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("HandleForm() error = %v, want %q", err, wantErr)
+	}
+}
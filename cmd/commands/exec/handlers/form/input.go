@@ -8,7 +8,6 @@ import (
 
 func HandleInput(input map[string]interface{}, vars map[string]interface{}) (*huh.Input, string, *string, error) {
 	var err error
-	data := vars["data"].(map[string]interface{})
 
 	label, err := execBuilders.BuildStringValue("label", input, vars, false, constants.InputComponent)
 	if err != nil {
@@ -26,14 +25,16 @@ func HandleInput(input map[string]interface{}, vars map[string]interface{}) (*hu
 	if err != nil {
 		return nil, "", nil, err
 	}
-	var outValue string
+	defaultValue, err := execBuilders.BuildStringValue("default", input, vars, false, constants.InputComponent)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	outValue := defaultValue
 	h := huh.NewInput().
 		Title(label).
 		Description(description).
 		Placeholder(placeholder).
 		Value(&outValue)
 
-	data[out] = out
-
 	return h, out, &outValue, nil
 }
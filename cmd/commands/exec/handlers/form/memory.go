@@ -0,0 +1,119 @@
+package execFormHandlers
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// AnswersFile is the state file with the answers of the form fields marked
+// with remember, by run.
+const AnswersFile = "answers.yaml"
+
+var (
+	// memory holds the remembered answers of every run of the project.
+	memory = map[string]map[string]interface{}{}
+
+	// memoryRun is the run whose answers are remembered and pre-filled.
+	memoryRun string
+
+	// memoryChanged reports whether an answer was remembered since the
+	// memory was loaded.
+	memoryChanged bool
+)
+
+// LoadMemory reads the remembered answers, which pre-fill the fields marked
+// with remember in the forms of the given run.
+func LoadMemory(run string) error {
+	memory = map[string]map[string]interface{}{}
+	memoryRun = run
+	memoryChanged = false
+	content, err := shared.ReadStateFile(AnswersFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading remembered answers error: %s", err.Error())
+	}
+	if err := yaml.Unmarshal(content, &memory); err != nil {
+		return fmt.Errorf("parsing remembered answers error: %s", err.Error())
+	}
+	if memory == nil {
+		memory = map[string]map[string]interface{}{}
+	}
+	return nil
+}
+
+// SaveMemory writes the remembered answers, if any of them changed. A dry run
+// writes nothing, so its answers are not remembered.
+func SaveMemory() error {
+	if !memoryChanged || shared.DryRun {
+		return nil
+	}
+	content, err := yaml.Marshal(memory)
+	if err != nil {
+		return fmt.Errorf("encoding remembered answers error: %s", err.Error())
+	}
+	if err := shared.WriteStateFile(AnswersFile, content); err != nil {
+		return fmt.Errorf("writing remembered answers error: %s", err.Error())
+	}
+	memoryChanged = false
+	return nil
+}
+
+func rememberAnswer(out string, value interface{}) {
+	if memoryRun == "" {
+		return
+	}
+	answers, ok := memory[memoryRun]
+	if !ok {
+		answers = map[string]interface{}{}
+		memory[memoryRun] = answers
+	}
+	answers[out] = value
+	memoryChanged = true
+}
+
+func rememberedAnswer(out string) (interface{}, bool) {
+	value, ok := memory[memoryRun][out]
+	return value, ok
+}
+
+// prefill sets the value bound to a field to a remembered answer, reporting
+// whether the answer fits the field.
+func prefill(outValue interface{}, answer interface{}) bool {
+	switch target := outValue.(type) {
+	case *string:
+		if str, ok := answer.(string); ok {
+			*target = str
+			return true
+		}
+	case *bool:
+		if b, ok := answer.(bool); ok {
+			*target = b
+			return true
+		}
+	case *[]string:
+		if values, ok := answer.([]string); ok {
+			*target = values
+			return true
+		}
+		list, ok := answer.([]interface{})
+		if !ok {
+			return false
+		}
+		values := []string{}
+		for _, item := range list {
+			str, ok := item.(string)
+			if !ok {
+				return false
+			}
+			values = append(values, str)
+		}
+		*target = values
+		return true
+	}
+	return false
+}
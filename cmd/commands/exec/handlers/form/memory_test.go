@@ -0,0 +1,101 @@
+package execFormHandlers
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestHandleForm_Remember(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	shared.NoInput = true
+	defer func() { shared.NoInput = false }()
+
+	form := map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"input": map[string]interface{}{"out": "team", "remember": true}},
+		map[string]interface{}{"multi-select": map[string]interface{}{
+			"out":      "features",
+			"remember": true,
+			"options":  []interface{}{map[string]interface{}{"label": "api"}, map[string]interface{}{"label": "worker"}},
+		}},
+		map[string]interface{}{"input": map[string]interface{}{"out": "name", "default": "users"}},
+	}}
+
+	if err := LoadMemory("build:service"); err != nil {
+		t.Fatalf("LoadMemory() error = %v", err)
+	}
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	if _, err := HandleForm(context.Background(), form, vars); err == nil || !strings.Contains(err.Error(), "missing value for team") {
+		t.Fatalf("HandleForm() error = %v, want missing value for team", err)
+	}
+
+	// answer the fields with --answers, which are remembered like typed answers
+	os.WriteFile("preset.yaml", []byte("team: platform\nfeatures: [worker]\n"), 0644)
+	shared.AnswersFile = "preset.yaml"
+	data, err := shared.BuildData()
+	shared.AnswersFile = ""
+	if err != nil {
+		t.Fatalf("BuildData() error = %v", err)
+	}
+	if _, err := HandleForm(context.Background(), form, map[string]interface{}{"data": data}); err != nil {
+		t.Fatalf("HandleForm() error = %v", err)
+	}
+	if err := SaveMemory(); err != nil {
+		t.Fatalf("SaveMemory() error = %v", err)
+	}
+	saved, err := shared.ReadStateFile(AnswersFile)
+	if err != nil {
+		t.Fatalf("ReadStateFile() error = %v", err)
+	}
+	for _, want := range []string{"build:service:", "team: platform", "- worker"} {
+		if !strings.Contains(string(saved), want) {
+			t.Errorf("%s = %q, want it to contain %q", AnswersFile, saved, want)
+		}
+	}
+	if strings.Contains(string(saved), "name:") {
+		t.Errorf("%s = %q, want only the fields with remember", AnswersFile, saved)
+	}
+	// forget the preset values before the next form
+	if _, err := shared.BuildData(); err != nil {
+		t.Fatalf("BuildData() error = %v", err)
+	}
+
+	if err := LoadMemory("build:service"); err != nil {
+		t.Fatalf("LoadMemory() error = %v", err)
+	}
+	answers, err := HandleForm(context.Background(), form, vars)
+	if err != nil {
+		t.Fatalf("HandleForm() error = %v", err)
+	}
+	want := map[string]interface{}{"team": "platform", "features": []string{"worker"}, "name": "users"}
+	if !reflect.DeepEqual(answers, want) {
+		t.Errorf("answers = %v, want %v", answers, want)
+	}
+
+	// a dry run remembers nothing
+	shared.DryRun = true
+	rememberAnswer("team", "dry")
+	err = SaveMemory()
+	shared.DryRun = false
+	if err != nil {
+		t.Fatalf("SaveMemory() in dry-run mode error = %v", err)
+	}
+	if saved, _ := shared.ReadStateFile(AnswersFile); strings.Contains(string(saved), "dry") {
+		t.Errorf("%s = %q, want the dry run answers left out", AnswersFile, saved)
+	}
+
+	if err := LoadMemory("other"); err != nil {
+		t.Fatalf("LoadMemory() error = %v", err)
+	}
+	if _, ok := rememberedAnswer("team"); ok {
+		t.Errorf("answers of build:service were remembered for another run")
+	}
+}
@@ -1,8 +1,12 @@
 package execFormHandlers
 
 import (
+	"fmt"
+
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/internal/functions"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
 	"github.com/charmbracelet/huh"
 )
 
@@ -43,7 +47,20 @@ func HandleMultiSelect(input map[string]interface{}, vars map[string]interface{}
 			options = append(options, huh.NewOption[string](label, value))
 		}
 
-		var outValue []string
+		outValue := []string{}
+		if defaults, ok := input["default"].([]interface{}); ok {
+			for _, d := range defaults {
+				defaultValue, ok := d.(string)
+				if !ok {
+					return nil, "", nil, fmt.Errorf("default must be a list of strings for %s", constants.MultiSelectComponent)
+				}
+				defaultValue, err = helpers.ReplaceVars(defaultValue, vars, functions.GetFuncMap())
+				if err != nil {
+					return nil, "", nil, err
+				}
+				outValue = append(outValue, defaultValue)
+			}
+		}
 		h := huh.NewMultiSelect[string]().
 			Title(label).
 			Description(description).
@@ -56,5 +73,5 @@ func HandleMultiSelect(input map[string]interface{}, vars map[string]interface{}
 
 		return h, out, &outValue, nil
 	}
-	return nil, out, nil, nil
+	return nil, "", nil, fmt.Errorf("options is required for %s", constants.MultiSelectComponent)
 }
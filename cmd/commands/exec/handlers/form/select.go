@@ -1,6 +1,8 @@
 package execFormHandlers
 
 import (
+	"fmt"
+
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
 	"github.com/charmbracelet/huh"
@@ -40,7 +42,10 @@ func HandleSelect(input map[string]interface{}, vars map[string]interface{}) (*h
 			options = append(options, huh.NewOption[string](label, value))
 		}
 
-		var outValue string
+		outValue, err := execBuilders.BuildStringValue("default", input, vars, false, constants.SelectComponent)
+		if err != nil {
+			return nil, "", nil, err
+		}
 		h := huh.NewSelect[string]().
 			Title(label).
 			Description(description).
@@ -49,5 +54,5 @@ func HandleSelect(input map[string]interface{}, vars map[string]interface{}) (*h
 
 		return h, out, &outValue, nil
 	}
-	return nil, out, nil, nil
+	return nil, "", nil, fmt.Errorf("options is required for %s", constants.SelectComponent)
 }
@@ -0,0 +1,245 @@
+package execHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/functions"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultHTTPTimeout is used by http steps that do not set a timeout.
+const defaultHTTPTimeout = 30 * time.Second
+
+// HandleHTTP sends the request of an http step and stores the parsed response
+// body in .data. The result holds the status, headers and parsed body of the
+// response. Requests other than GET are skipped in dry-run mode.
+func HandleHTTP(ctx context.Context, params map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
+	data := vars["data"].(map[string]interface{})
+
+	method, err := execBuilders.BuildStringValue("method", params, vars, false, constants.HttpHandler)
+	if err != nil {
+		return nil, err
+	}
+	method = strings.ToUpper(method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	url, err := execBuilders.BuildStringValue("url", params, vars, true, constants.HttpHandler)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := execBuilders.BuildDurationValue("timeout", params, vars, false, constants.HttpHandler)
+	if err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		timeout = defaultHTTPTimeout
+	}
+	statuses, err := buildStatuses(params["status"], vars)
+	if err != nil {
+		return nil, err
+	}
+	out, err := execBuilders.BuildStringValue("out", params, vars, false, constants.HttpHandler)
+	if err != nil {
+		return nil, err
+	}
+	statusOut, err := execBuilders.BuildStringValue("status-out", params, vars, false, constants.HttpHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	if h, ok := params["headers"]; ok {
+		headerMap, ok := h.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("headers must be a map")
+		}
+		for key, value := range headerMap {
+			str, err := helpers.ReplaceVars(fmt.Sprint(value), vars, functions.GetFuncMap())
+			if err != nil {
+				return nil, fmt.Errorf("parsing header %s error: %s", key, err.Error())
+			}
+			headers.Set(key, str)
+		}
+	}
+	body, err := buildBody(params["body"], headers, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if shared.DryRun && method != http.MethodGet {
+		shared.PlannedCommands = append(shared.PlannedCommands, method+" "+url)
+		style.LogPrint(fmt.Sprintf("skipping (dry run): %s %s", method, url))
+		return skippedResult(), nil
+	}
+	style.LogPrint(fmt.Sprintf("requesting: %s %s", method, url))
+
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(requestCtx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request error: %s", err.Error())
+	}
+	req.Header = headers
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, stopError(ctx)
+		}
+		return nil, fmt.Errorf("request error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response error: %s", err.Error())
+	}
+
+	parsed, err := parseResponse(resp.Header.Get("Content-Type"), content)
+	if err != nil {
+		if out != "" {
+			return nil, err
+		}
+		parsed = string(content)
+	}
+	responseHeaders := map[string]interface{}{}
+	for key := range resp.Header {
+		responseHeaders[key] = resp.Header.Get(key)
+	}
+	result := map[string]interface{}{
+		"status":  resp.StatusCode,
+		"headers": responseHeaders,
+		"body":    parsed,
+	}
+
+	if !expectedStatus(statuses, resp.StatusCode) {
+		return result, fmt.Errorf("unexpected status %s: %s", resp.Status, truncate(strings.TrimSpace(string(content)), 200))
+	}
+	if statusOut != "" {
+		data[statusOut] = resp.StatusCode
+	}
+	if out != "" {
+		data[out] = parsed
+	}
+	return result, nil
+}
+
+// buildBody encodes the body of the request. Strings are sent as they are,
+// other values are encoded as YAML when the Content-Type says so, or as JSON
+// otherwise.
+func buildBody(body interface{}, headers http.Header, vars map[string]interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	value, err := helpers.ReplaceVarsInValue(body, vars, functions.GetFuncMap())
+	if err != nil {
+		return nil, fmt.Errorf("parsing body error: %s", err.Error())
+	}
+	if str, ok := value.(string); ok {
+		return []byte(str), nil
+	}
+	if strings.Contains(headers.Get("Content-Type"), "yaml") {
+		return yaml.Marshal(value)
+	}
+	if headers.Get("Content-Type") == "" {
+		headers.Set("Content-Type", "application/json")
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding body error: %s", err.Error())
+	}
+	return content, nil
+}
+
+// buildStatuses reads the expected status codes, given as a number or a list.
+func buildStatuses(status interface{}, vars map[string]interface{}) ([]int, error) {
+	list, ok := status.([]interface{})
+	if !ok {
+		if status == nil {
+			return nil, nil
+		}
+		list = []interface{}{status}
+	}
+	statuses := []int{}
+	for _, s := range list {
+		str, err := helpers.ReplaceVars(fmt.Sprint(s), vars, functions.GetFuncMap())
+		if err != nil {
+			return nil, err
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(str))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status: %s", str)
+		}
+		statuses = append(statuses, code)
+	}
+	return statuses, nil
+}
+
+// expectedStatus reports whether code is one of the expected statuses, or a
+// 2xx status when none is expected.
+func expectedStatus(statuses []int, code int) bool {
+	if len(statuses) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, status := range statuses {
+		if status == code {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResponse decodes JSON and YAML responses. Other responses are
+// returned as a string.
+func parseResponse(contentType string, content []byte) (interface{}, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	var value interface{}
+	switch {
+	case len(bytes.TrimSpace(content)) == 0:
+		return "", nil
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if err := json.Unmarshal(content, &value); err != nil {
+			return nil, fmt.Errorf("parsing JSON response error: %s", err.Error())
+		}
+	case strings.Contains(mediaType, "yaml"):
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			return nil, fmt.Errorf("parsing YAML response error: %s", err.Error())
+		}
+	default:
+		return string(content), nil
+	}
+	return value, nil
+}
+
+// truncate shortens text to size characters, never cutting a multi-byte
+// character in half.
+func truncate(text string, size int) string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return text
+	}
+	return string(runes[:size]) + "..."
+}
+
+func init() {
+	Register(constants.HttpHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.HttpHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleHTTP(ctx, params, vars)
+	}))
+}
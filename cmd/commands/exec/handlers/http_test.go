@@ -0,0 +1,136 @@
+package execHandlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestHandleHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services":
+			if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "svc-" + body["name"].(string)})
+		case "/config":
+			w.Header().Set("Content-Type", "application/yaml")
+			io.WriteString(w, "region: eu\n")
+		case "/text":
+			io.WriteString(w, "pong")
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{"name": "users", "token": "token", "url": server.URL},
+	}
+	steps := []interface{}{
+		map[string]interface{}{"http": map[string]interface{}{
+			"method":     "post",
+			"url":        "{{.data.url}}/services",
+			"headers":    map[string]interface{}{"Authorization": "Bearer {{.data.token}}"},
+			"body":       map[string]interface{}{"name": "{{.data.name}}"},
+			"status":     201,
+			"out":        "service",
+			"status-out": "status",
+		}},
+		map[string]interface{}{"http": map[string]interface{}{"url": "{{.data.url}}/config", "out": "config"}},
+		map[string]interface{}{"http": map[string]interface{}{"url": "{{.data.url}}/text", "out": "text"}},
+	}
+	if err := HandleSteps(context.Background(), steps, "", vars); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+
+	data := vars["data"].(map[string]interface{})
+	if got := data["service"].(map[string]interface{})["id"]; got != "svc-users" {
+		t.Errorf("service.id = %v, want svc-users", got)
+	}
+	if got := data["status"]; got != 201 {
+		t.Errorf("status = %v, want 201", got)
+	}
+	if got := data["config"].(map[string]interface{})["region"]; got != "eu" {
+		t.Errorf("config.region = %v, want eu", got)
+	}
+	if got := data["text"]; got != "pong" {
+		t.Errorf("text = %v, want pong", got)
+	}
+
+	errorCases := map[string]map[string]interface{}{
+		"unexpected status 404": {"url": server.URL + "/missing"},
+		"unexpected status 200": {"url": server.URL + "/text", "status": []interface{}{201, 202}},
+		"deadline exceeded":     {"url": server.URL + "/slow", "timeout": "50ms"},
+	}
+	for want, params := range errorCases {
+		_, err := HandleHTTP(context.Background(), params, vars)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("HandleHTTP(context.Background(), %v) error = %v, want it to contain %q", params, err, want)
+		}
+	}
+}
+
+func TestHandleHTTP_DryRun(t *testing.T) {
+	shared.DryRun = true
+	defer func() {
+		shared.DryRun = false
+		shared.PlannedCommands = nil
+	}()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	result, err := HandleHTTP(context.Background(), map[string]interface{}{"method": "DELETE", "url": server.URL}, vars)
+	if err != nil {
+		t.Fatalf("HandleHTTP() error = %v", err)
+	}
+	if !reflect.DeepEqual(result, skippedResult()) {
+		t.Errorf("HandleHTTP() = %v, want the skipped result", result)
+	}
+	if requests != 0 {
+		t.Errorf("the DELETE request was sent in dry-run mode")
+	}
+	if len(shared.PlannedCommands) != 1 || shared.PlannedCommands[0] != "DELETE "+server.URL {
+		t.Errorf("PlannedCommands = %v", shared.PlannedCommands)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text string
+		size int
+		want string
+	}{
+		{text: "short", size: 10, want: "short"},
+		{text: "exactly", size: 7, want: "exactly"},
+		{text: "truncated", size: 5, want: "trunc..."},
+		{text: "ação inválida", size: 3, want: "açã..."},
+		{text: "日本語のエラー", size: 2, want: "日本..."},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.text, tt.size); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.size, got, tt.want)
+		}
+	}
+}
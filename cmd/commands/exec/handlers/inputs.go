@@ -0,0 +1,54 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+
+	execFormHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers/form"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/internal/domain"
+)
+
+// handleInputs prompts for the required inputs of a run that have no value
+// and then validates and coerces every input into .data.
+func handleInputs(ctx context.Context, run *domain.Run, vars map[string]interface{}) error {
+	data := vars["data"].(map[string]interface{})
+	fields := []interface{}{}
+	for _, input := range run.Inputs {
+		if input.Required && input.Missing(data) {
+			fields = append(fields, inputField(input))
+		}
+	}
+	if len(fields) > 0 {
+		_, err := execFormHandlers.HandleForm(ctx, map[string]interface{}{
+			"title":       run.Key,
+			"description": run.Description,
+			"fields":      fields,
+		}, vars)
+		if err != nil {
+			return fmt.Errorf("[inputs] - %w", err)
+		}
+	}
+	return domain.ResolveInputs(run.Inputs, data)
+}
+
+// inputField builds the form field used to prompt for an input.
+func inputField(input domain.RunInput) map[string]interface{} {
+	field := map[string]interface{}{
+		"label":       input.Name,
+		"description": input.Description,
+		"out":         input.Name,
+	}
+	if len(input.Enum) > 0 {
+		options := []interface{}{}
+		for _, value := range input.Enum {
+			options = append(options, map[string]interface{}{"label": fmt.Sprint(value)})
+		}
+		field["options"] = options
+		return map[string]interface{}{constants.SelectComponent: field}
+	}
+	if input.Type == domain.BoolInput {
+		return map[string]interface{}{constants.ConfirmComponent: field}
+	}
+	return map[string]interface{}{constants.InputComponent: field}
+}
@@ -0,0 +1,187 @@
+package execHandlers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"gopkg.in/yaml.v3"
+)
+
+// JournalFile is the state file where the progress of a run is saved.
+const JournalFile = "journal.yaml"
+
+// Journal records the progress of a run so that it can be resumed from the
+// step that failed.
+type Journal struct {
+	Run    string `yaml:"run"`
+	Module string `yaml:"module"`
+
+	// Completed lists the paths of the steps that finished successfully, e.g.
+	// "init/3/setup/0" for the first step of the run called by the fourth
+	// step of init.
+	Completed []string `yaml:"completed"`
+
+	// Vars is the snapshot of the run variables after the last completed step.
+	Vars map[string]interface{} `yaml:"vars"`
+
+	// Error is the message of the error that stopped the run.
+	Error string `yaml:"error,omitempty"`
+}
+
+var (
+	// journal is the journal of the current run, nil when it is not recorded.
+	journal *Journal
+
+	// completed indexes the completed steps of the journal.
+	completed = map[string]bool{}
+
+	// stepPath is the path of the step being executed.
+	stepPath []string
+
+	// journalVars are the variables of the recorded run, copied to the
+	// journal every time a step completes.
+	journalVars map[string]interface{}
+)
+
+// LoadJournal reads the journal left by the last failed run.
+func LoadJournal() (*Journal, error) {
+	content, err := shared.ReadStateFile(JournalFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("there is no failed run to resume")
+		}
+		return nil, fmt.Errorf("reading journal error: %s", err.Error())
+	}
+	j := &Journal{}
+	if err := yaml.Unmarshal(content, j); err != nil {
+		return nil, fmt.Errorf("parsing journal error: %s", err.Error())
+	}
+	if j.Vars == nil {
+		j.Vars = map[string]interface{}{}
+	}
+	if _, ok := j.Vars["data"].(map[string]interface{}); !ok {
+		j.Vars["data"] = map[string]interface{}{}
+	}
+	return j, nil
+}
+
+// startJournal starts recording the progress of a run whose variables are
+// vars. The steps already completed by a resumed journal are skipped.
+func startJournal(j *Journal, vars map[string]interface{}) {
+	journal = j
+	journal.Vars = helpers.CopyMap(vars)
+	journalVars = vars
+	completed = map[string]bool{}
+	for _, path := range j.Completed {
+		completed[path] = true
+	}
+}
+
+// finishJournal removes the journal of a successful run, or saves it with the
+// error that stopped the run so that it can be resumed. Runs that failed
+// before completing any step leave nothing to resume. The saved variables are
+// those after the last completed step, without the changes of the step that
+// failed.
+func finishJournal(runErr error) error {
+	if journal == nil {
+		return nil
+	}
+	defer func() { journal, journalVars = nil, nil }()
+	if runErr == nil || len(journal.Completed) == 0 {
+		return shared.RemoveStateFile(JournalFile)
+	}
+	journal.Error = runErr.Error()
+	if err := saveJournal(); err != nil {
+		return err
+	}
+	style.LogPrint("run progress saved, fix the error and use --resume to continue from the failed step")
+	return nil
+}
+
+// discardJournal forgets the progress of the current run, so that nothing is
+// left to resume.
+func discardJournal() {
+	if journal != nil {
+		journal.Completed = nil
+	}
+}
+
+func saveJournal() error {
+	content, err := yaml.Marshal(journal)
+	if err != nil {
+		return fmt.Errorf("encoding journal error: %s", err.Error())
+	}
+	if err := shared.WriteStateFile(JournalFile, content); err != nil {
+		return fmt.Errorf("writing journal error: %s", err.Error())
+	}
+	return nil
+}
+
+// withStepPath executes fn with segment appended to the current step path.
+func withStepPath(segment string, fn func() error) error {
+	stepPath = append(stepPath, segment)
+	defer func() { stepPath = stepPath[:len(stepPath)-1] }()
+	return fn()
+}
+
+func currentStepPath() string {
+	return strings.Join(stepPath, "/")
+}
+
+// isStepCompleted reports whether the step was completed by the resumed run.
+func isStepCompleted(path string) bool {
+	return journal != nil && completed[path]
+}
+
+// completeStep records a step as completed together with the current
+// variables of the run. The variables of nested runs with their own scope are
+// not saved, their calls are executed again when the run is resumed.
+func completeStep(path string) error {
+	if journal == nil {
+		return nil
+	}
+	completed[path] = true
+	journal.Completed = append(journal.Completed, path)
+	journal.Vars = helpers.CopyMap(journalVars)
+	return saveJournal()
+}
+
+// forgetSteps forgets the completed steps nested inside a step, so that they
+// are executed again when the step is retried.
+func forgetSteps(path string) {
+	if journal == nil {
+		return
+	}
+	prefix := path + "/"
+	kept := journal.Completed[:0]
+	for _, p := range journal.Completed {
+		if strings.HasPrefix(p, prefix) {
+			delete(completed, p)
+			continue
+		}
+		kept = append(kept, p)
+	}
+	journal.Completed = kept
+}
+
+// savedVars returns the variables saved by the last completed step.
+func savedVars() map[string]interface{} {
+	if journal == nil {
+		return nil
+	}
+	return journal.Vars
+}
+
+// restoreSteps forgets the completed steps nested inside a step together with
+// the variables they saved, going back to the variables saved before them.
+func restoreSteps(path string, vars map[string]interface{}) {
+	if journal == nil {
+		return
+	}
+	forgetSteps(path)
+	journal.Vars = vars
+}
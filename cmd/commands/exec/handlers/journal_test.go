@@ -0,0 +1,67 @@
+package execHandlers
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestExecuteRun_Resume(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	defer func() { shared.Resume = false }()
+
+	os.MkdirAll(shared.KumaRunsPath, 0755)
+	os.WriteFile(shared.KumaRunsPath+"/build.yaml", []byte(`
+build:
+  steps:
+    - cmd: sh -c 'echo first >> trail'
+    - define: {variable: version, value: "v1"}
+    - cmd: test -f fixed
+      register: probe
+      on-error:
+        - define: {variable: polluted, value: "yes"}
+        - cmd: "false"
+    - cmd: sh -c 'echo last {{ .data.version }} >> trail'
+`), 0644)
+
+	err := ExecuteRun(context.Background(), "build", "")
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+		t.Fatalf("ExecuteRun() error = %v, want the third step to fail", err)
+	}
+	j, err := LoadJournal()
+	if err != nil {
+		t.Fatalf("LoadJournal() error = %v", err)
+	}
+	if got := strings.Join(j.Completed, ","); got != "build/0,build/1" {
+		t.Errorf("completed = %s, want build/0,build/1", got)
+	}
+	data := j.Vars["data"].(map[string]interface{})
+	if data["version"] != "v1" {
+		t.Errorf("journal data = %v, want version v1", data)
+	}
+	for _, key := range []string{"probe", "polluted"} {
+		if _, ok := data[key]; ok {
+			t.Errorf("journal data has %s, want the vars after the last completed step", key)
+		}
+	}
+
+	os.WriteFile("fixed", nil, 0644)
+	shared.Resume = true
+	if err := ExecuteRun(context.Background(), "", ""); err != nil {
+		t.Fatalf("ExecuteRun() resumed error = %v", err)
+	}
+	trail, _ := os.ReadFile("trail")
+	if got := string(trail); got != "first\nlast v1\n" {
+		t.Errorf("trail = %q, want the completed steps skipped on resume", got)
+	}
+	if _, err := LoadJournal(); err == nil {
+		t.Errorf("LoadJournal() found a journal after the resumed run succeeded")
+	}
+}
@@ -1,33 +1,32 @@
 package execHandlers
 
 import (
+	"context"
 	"fmt"
 	"net/url"
-	"os"
 	"strings"
 
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
 	"github.com/arthurbcp/kuma/v2/internal/helpers"
-	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
-	"github.com/arthurbcp/kuma/v2/pkg/style"
 	"github.com/charmbracelet/huh/spinner"
-	"github.com/spf13/afero"
 )
 
-func HandleLoad(load map[string]interface{}, vars map[string]interface{}) error {
+// HandleLoad reads a variables file, from a path or URL, and returns it.
+func HandleLoad(ctx context.Context, load map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	data := vars["data"].(map[string]interface{})
-	fs := filesystem.NewFileSystem(afero.NewOsFs())
+	fs := shared.GetFileSystem()
 
 	from, err := execBuilders.BuildStringValue("from", load, vars, true, constants.LoadHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	out, err := execBuilders.BuildStringValue("out", load, vars, true, constants.LoadHandler)
+	out, err := execBuilders.BuildStringValue("out", load, vars, false, constants.LoadHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var fileVars map[string]interface{}
@@ -35,30 +34,46 @@ func HandleLoad(load map[string]interface{}, vars map[string]interface{}) error
 	if err != nil {
 		fileVars, err = helpers.UnmarshalFile(from, fs)
 		if err != nil {
-			return fmt.Errorf("[handler:load] - parsing file error: %s", err.Error())
+			return nil, fmt.Errorf("[handler:load] - parsing file error: %s", err.Error())
 		}
 	} else {
+		var downloadErr error
 		err = spinner.New().
 			Title("Downloading variables file").
 			Action(func() {
-				varsContent, err := fs.ReadFileFromURL(from)
-				if err != nil {
-					style.ErrorPrint("[handler:load] - reading file error: " + err.Error())
-					os.Exit(1)
+				var varsContent string
+				varsContent, downloadErr = fs.ReadFileFromURL(ctx, from)
+				if downloadErr != nil {
+					downloadErr = fmt.Errorf("[handler:load] - reading file error: %s", downloadErr.Error())
+					return
 				}
 				splitURIPath := strings.Split(parsedURI.Path, "/")
-				fileVars, err = helpers.UnmarshalByExt(splitURIPath[len(splitURIPath)-1], []byte(varsContent))
-				if err != nil {
-					style.ErrorPrint("[handler:load] - parsing file error: " + err.Error())
-					os.Exit(1)
+				fileVars, downloadErr = helpers.UnmarshalByExt(splitURIPath[len(splitURIPath)-1], []byte(varsContent))
+				if downloadErr != nil {
+					downloadErr = fmt.Errorf("[handler:load] - parsing file error: %s", downloadErr.Error())
 				}
 			}).
 			Run()
 
 		if err != nil {
-			return fmt.Errorf("[handler:load] - downloading variables file error: %s", err.Error())
+			return nil, fmt.Errorf("[handler:load] - downloading variables file error: %s", err.Error())
 		}
+		if downloadErr != nil {
+			return nil, downloadErr
+		}
+	}
+	if out != "" {
+		data[out] = fileVars
 	}
-	data[out] = fileVars
-	return nil
+	return fileVars, nil
+}
+
+func init() {
+	Register(constants.LoadHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.LoadHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleLoad(ctx, params, vars)
+	}))
 }
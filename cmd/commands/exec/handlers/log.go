@@ -1,21 +1,34 @@
 package execHandlers
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
 	"github.com/arthurbcp/kuma/v2/internal/functions"
 	"github.com/arthurbcp/kuma/v2/internal/helpers"
 	"github.com/arthurbcp/kuma/v2/pkg/style"
 )
 
-func HandleLog(log string, vars map[string]interface{}) error {
+// HandleLog prints a message and returns it.
+func HandleLog(log string, vars map[string]interface{}) (string, error) {
 	var err error
 
 	log, err = helpers.ReplaceVars(log, vars, functions.GetFuncMap())
 	if err != nil {
-		return fmt.Errorf("parsing log error: %s", err.Error())
+		return "", fmt.Errorf("parsing log error: %s", err.Error())
 	}
 
 	style.LogPrint(log)
-	return nil
+	return log, nil
+}
+
+func init() {
+	Register(constants.LogHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		log, err := stringParam(constants.LogHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleLog(log, vars)
+	}))
 }
@@ -1,6 +1,7 @@
 package execHandlers
 
 import (
+	"context"
 	"fmt"
 
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
@@ -9,54 +10,80 @@ import (
 	"github.com/arthurbcp/kuma/v2/cmd/shared"
 	"github.com/arthurbcp/kuma/v2/internal/functions"
 	"github.com/arthurbcp/kuma/v2/internal/helpers"
-	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
 	"github.com/arthurbcp/kuma/v2/pkg/style"
-	"github.com/spf13/afero"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
-func HandleModify(module string, data map[string]interface{}, vars map[string]interface{}) error {
+// HandleModify applies a template to a file. The result holds the file, whether
+// its content changed and the unified diff of the change.
+func HandleModify(module string, data map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
 	path := shared.KumaFilesPath
-	fs := filesystem.NewFileSystem(afero.NewOsFs())
+	fs := shared.GetFileSystem()
 	if module != "" {
 		path = shared.KumaFilesPath + "/" + module + "/" + shared.KumaFilesPath
 	}
 	file, err := execBuilders.BuildStringValue("file", data, vars, true, constants.ModifyHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	fileContent, err := fs.ReadFile(file)
 	if err != nil {
 		_, err = fs.CreateFile(file)
 		if err != nil {
-			return fmt.Errorf("creating file error: %s", err.Error())
+			return nil, fmt.Errorf("creating file error: %s", err.Error())
 		}
 		fileContent = ""
 	}
 	template, err := execBuilders.BuildStringValue("template", data, vars, true, constants.ModifyHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	codeMark, err := execBuilders.BuildStringValue("mark", data, vars, false, constants.ModifyHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	action, err := execBuilders.BuildStringValue("action", data, vars, false, constants.ModifyHandler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	templateContent, err := fs.ReadFile(path + "/" + template)
 	if err != nil {
-		return fmt.Errorf("reading template file error: %s", err.Error())
+		return nil, fmt.Errorf("reading template file error: %s", err.Error())
 	}
 	templateContent, err = helpers.ReplaceVars(templateContent, vars, functions.GetFuncMap())
 	if err != nil {
-		return fmt.Errorf("parsing template file error: %s", err.Error())
+		return nil, fmt.Errorf("parsing template file error: %s", err.Error())
 	}
+	original := fileContent
 	fileContent = modify.HandleAction(action, fileContent, templateContent, codeMark)
 	err = fs.WriteFile(file, fileContent)
 	if err != nil {
-		return fmt.Errorf("writing file error: %s", err.Error())
+		return nil, fmt.Errorf("writing file error: %s", err.Error())
 	}
 	style.CheckMarkPrint(fmt.Sprintf("file %s modified successfully!", file))
-	return nil
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(original),
+		B:        difflib.SplitLines(fileContent),
+		FromFile: "a/" + file,
+		ToFile:   "b/" + file,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("diffing file error: %s", err.Error())
+	}
+	return map[string]interface{}{
+		"file":    file,
+		"changed": original != fileContent,
+		"diff":    diff,
+	}, nil
+}
+
+func init() {
+	Register(constants.ModifyHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.ModifyHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleModify(module, params, vars)
+	}))
 }
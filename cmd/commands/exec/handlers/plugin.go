@@ -0,0 +1,123 @@
+package execHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+)
+
+// PluginPrefix is the prefix of the executables used as external handlers:
+// a step using the key "deploy" runs the "kuma-handler-deploy" executable.
+const PluginPrefix = "kuma-handler-"
+
+// pluginRequest is written as JSON to the standard input of a plugin.
+type pluginRequest struct {
+	Handler string                 `json:"handler"`
+	Module  string                 `json:"module"`
+	Params  interface{}            `json:"params"`
+	Vars    map[string]interface{} `json:"vars"`
+}
+
+// pluginResponse is read as JSON from the standard output of a plugin.
+type pluginResponse struct {
+	// Data is merged into the .data variables of the run.
+	Data map[string]interface{} `json:"data"`
+
+	// Error makes the step fail with the given message.
+	Error string `json:"error"`
+}
+
+// PluginHandler executes an external kuma-handler-* executable.
+type PluginHandler struct {
+	Name string
+	Path string
+}
+
+// resolveHandler returns the registered handler for name, falling back to
+// a plugin executable when no handler is registered under it.
+func resolveHandler(module string, name string) (Handler, error) {
+	handler, err := GetHandler(name)
+	var unknown *UnknownHandlerError
+	if !errors.As(err, &unknown) {
+		return handler, err
+	}
+	if path, ok := findPlugin(module, name); ok {
+		return &PluginHandler{Name: name, Path: path}, nil
+	}
+	return nil, err
+}
+
+// findPlugin looks for the plugin executable of a handler inside the handlers
+// directory of the module (or of the project, without a module) and then on
+// the PATH.
+func findPlugin(module string, name string) (string, bool) {
+	dir := shared.KumaFilesPath + "/handlers"
+	if module != "" {
+		dir = shared.KumaFilesPath + "/" + module + "/" + shared.KumaFilesPath + "/handlers"
+	}
+	if path, err := exec.LookPath(filepath.Join(dir, PluginPrefix+name)); err == nil {
+		return path, true
+	}
+	if path, err := exec.LookPath(PluginPrefix + name); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// Handle sends the step params and the run vars to the plugin and merges the
+// data it answers into the run vars. The data is also the step result. The
+// plugin is killed when ctx is canceled.
+func (h *PluginHandler) Handle(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+	if shared.DryRun {
+		shared.PlannedCommands = append(shared.PlannedCommands, h.Path)
+		style.LogPrint(fmt.Sprintf("skipping (dry run): %s", h.Path))
+		return skippedResult(), nil
+	}
+
+	request, err := json.Marshal(pluginRequest{
+		Handler: h.Name,
+		Module:  module,
+		Params:  value,
+		Vars:    vars,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding plugin request error: %s", err.Error())
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, stopError(ctx)
+	}
+
+	response := pluginResponse{}
+	if stdout.Len() > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+			return nil, fmt.Errorf("decoding plugin %s response error: %s", h.Path, err.Error())
+		}
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("plugin %s error: %s", h.Path, response.Error)
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("plugin %s error: %s", h.Path, runErr.Error())
+	}
+
+	data := vars["data"].(map[string]interface{})
+	for key, value := range response.Data {
+		data[key] = value
+	}
+	return response.Data, nil
+}
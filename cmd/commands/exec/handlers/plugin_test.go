@@ -0,0 +1,104 @@
+package execHandlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+// writePlugin creates a kuma-handler-<name> shell script on a temporary PATH.
+func writePlugin(t *testing.T, name string, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, PluginPrefix+name), []byte("#!/bin/sh\n"+script), 0755)
+	if err != nil {
+		t.Fatalf("writing plugin error: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPluginHandler(t *testing.T) {
+	writePlugin(t, "echo-params", `
+input=$(cat)
+case "$input" in
+  *'"params":{"service":"users"}'*'"name":"kuma"'*) echo '{"data":{"registered":"users"}}' ;;
+  *) echo '{"error":"unexpected request"}' ;;
+esac
+`)
+	writePlugin(t, "fail", `cat > /dev/null; echo '{"error":"catalog is down"}'; exit 1`)
+
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{"name": "kuma"},
+	}
+	steps := []interface{}{
+		map[string]interface{}{"echo-params": map[string]interface{}{"service": "users"}},
+	}
+	if err := HandleSteps(context.Background(), steps, "", vars); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+	if got := vars["data"].(map[string]interface{})["registered"]; got != "users" {
+		t.Errorf("plugin data = %v, want %v", got, "users")
+	}
+
+	steps = []interface{}{
+		map[string]interface{}{"fail": map[string]interface{}{}},
+	}
+	err := HandleSteps(context.Background(), steps, "", vars)
+	if err == nil || !strings.Contains(err.Error(), "catalog is down") {
+		t.Errorf("HandleSteps() error = %v, want the plugin error message", err)
+	}
+}
+
+func TestFindPlugin(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	answer := func(source string) []byte {
+		return []byte("#!/bin/sh\ncat > /dev/null; echo '{\"data\":{\"source\":\"" + source + "\"}}'\n")
+	}
+	dirs := map[string]string{
+		shared.KumaFilesPath + "/handlers":                                   "project",
+		shared.KumaFilesPath + "/lint/" + shared.KumaFilesPath + "/handlers": "module",
+	}
+	for dir, source := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, PluginPrefix+"greet"), answer(source), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePlugin(t, "greet", `cat > /dev/null; echo '{"data":{"source":"path"}}'`)
+	writePlugin(t, "path-only", `cat > /dev/null; echo '{"data":{"source":"path"}}'`)
+
+	testCases := []struct {
+		name, module, handler, want string
+	}{
+		{name: "Project handlers", module: "", handler: "greet", want: "project"},
+		{name: "Module handlers", module: "lint", handler: "greet", want: "module"},
+		{name: "PATH", module: "lint", handler: "path-only", want: "path"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars := map[string]interface{}{"data": map[string]interface{}{}}
+			steps := []interface{}{map[string]interface{}{tc.handler: map[string]interface{}{}}}
+			if err := HandleSteps(context.Background(), steps, tc.module, vars); err != nil {
+				t.Fatalf("HandleSteps() error = %v", err)
+			}
+			if got := vars["data"].(map[string]interface{})["source"]; got != tc.want {
+				t.Errorf("source = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, ok := findPlugin("", "missing"); ok {
+		t.Errorf("findPlugin() found a plugin that does not exist")
+	}
+}
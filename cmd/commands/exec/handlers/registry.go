@@ -0,0 +1,130 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+)
+
+// Handler executes the value of a step key, e.g. the command of a `cmd` step.
+// It returns the result of the step, e.g. the output of the command, which is
+// stored in .data when the step sets register. The result can be returned
+// together with an error. ctx is canceled when the run is interrupted or
+// times out.
+type Handler interface {
+	Handle(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error)
+}
+
+// HandlerFunc adapts an ordinary function to the Handler interface.
+type HandlerFunc func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error)
+
+// Handle calls f(ctx, module, value, vars).
+func (f HandlerFunc) Handle(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+	return f(ctx, module, value, vars)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Handler{}
+)
+
+// Register makes a handler available to the steps of every run under the
+// given key. It panics if the key is empty, already registered or reserved
+// for a step modifier, or if the handler is nil.
+func Register(name string, handler Handler) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" {
+		panic("kuma: Register handler name is empty")
+	}
+	if handler == nil {
+		panic("kuma: Register handler is nil for " + name)
+	}
+	if stepModifiers[name] {
+		panic("kuma: Register handler name is a step modifier: " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("kuma: Register called twice for handler " + name)
+	}
+	registry[name] = handler
+}
+
+// Handlers returns the sorted keys of every registered handler.
+func Handlers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetHandler returns the handler registered under name, or an
+// *UnknownHandlerError when there is none.
+func GetHandler(name string) (Handler, error) {
+	registryMu.RLock()
+	handler, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, &UnknownHandlerError{
+			Name:       name,
+			Suggestion: closestHandler(name),
+		}
+	}
+	return handler, nil
+}
+
+// UnknownHandlerError is returned when a step uses a key that is neither a
+// registered handler nor a step modifier.
+type UnknownHandlerError struct {
+	// Name is the unknown key.
+	Name string
+
+	// Suggestion is the closest known handler name, if any is close enough.
+	Suggestion string
+}
+
+func (e *UnknownHandlerError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown handler: %s, did you mean %s?", e.Name, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown handler: %s", e.Name)
+}
+
+// closestHandler returns the registered handler name with the smallest edit
+// distance to name, as long as the distance is small enough to be a typo.
+func closestHandler(name string) string {
+	suggestion := ""
+	best := max(len(name)/2, 2) + 1
+	for _, candidate := range Handlers() {
+		distance := helpers.Levenshtein(name, candidate)
+		if distance < best {
+			best = distance
+			suggestion = candidate
+		}
+	}
+	return suggestion
+}
+
+// stringParam returns the value of a step that must be a string.
+func stringParam(handler string, value interface{}) (string, error) {
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", handler)
+	}
+	return str, nil
+}
+
+// mapParam returns the value of a step that must be a map.
+func mapParam(handler string, value interface{}) (map[string]interface{}, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map", handler)
+	}
+	return params, nil
+}
@@ -0,0 +1,75 @@
+package execHandlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// unregister removes a handler registered by a test, so that it does not
+// leak into the other tests of the package.
+func unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, name)
+}
+
+func TestRegister(t *testing.T) {
+	var got interface{}
+	t.Cleanup(func() { unregister("test-register") })
+	Register("test-register", HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		got = value
+		return nil, nil
+	}))
+
+	steps := []interface{}{
+		map[string]interface{}{"test-register": "value"},
+	}
+	if err := HandleSteps(context.Background(), steps, "", map[string]interface{}{"data": map[string]interface{}{}}); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+	if got != "value" {
+		t.Errorf("registered handler received %v, want %v", got, "value")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() should panic when the handler is already registered")
+		}
+	}()
+	Register("test-register", HandlerFunc(func(context.Context, string, interface{}, map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	}))
+}
+
+func TestGetHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		wantErr        bool
+		wantSuggestion string
+	}{
+		{"Built-in handler", "cmd", false, ""},
+		{"Typo", "crate", true, "create"},
+		{"Too different", "deploy-to-production", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetHandler(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var unknown *UnknownHandlerError
+			if !errors.As(err, &unknown) {
+				t.Fatalf("GetHandler() error = %T, want *UnknownHandlerError", err)
+			}
+			if unknown.Suggestion != tt.wantSuggestion {
+				t.Errorf("GetHandler() suggestion = %q, want %q", unknown.Suggestion, tt.wantSuggestion)
+			}
+		})
+	}
+}
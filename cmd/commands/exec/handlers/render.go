@@ -0,0 +1,70 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/functions"
+	"github.com/arthurbcp/kuma/v2/internal/handlers"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+)
+
+// HandleRender executes a single template with the run vars, writing the
+// result to the output file or, without one, to the standard output. The result
+// holds the output file, empty for the standard output.
+func HandleRender(module string, params map[string]interface{}, vars map[string]interface{}) (map[string]interface{}, error) {
+	path := shared.KumaFilesPath
+	fs := shared.GetFileSystem()
+	if module != "" {
+		path = shared.KumaFilesPath + "/" + module + "/" + shared.KumaFilesPath
+	}
+	template, err := execBuilders.BuildStringValue("template", params, vars, true, constants.RenderHandler)
+	if err != nil {
+		return nil, err
+	}
+	output, err := execBuilders.BuildStringValue("output", params, vars, false, constants.RenderHandler)
+	if err != nil {
+		return nil, err
+	}
+	includes, err := helpers.ReplaceVarsInValue(params["includes"], vars, functions.GetFuncMap())
+	if err != nil {
+		return nil, fmt.Errorf("parsing includes error: %s", err.Error())
+	}
+
+	tmpl, err := handlers.ParseTemplate(fs, path, map[string]interface{}{
+		"template": template,
+		"includes": includes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output == "" || output == "-" {
+		return map[string]interface{}{"file": ""}, tmpl.Execute(os.Stdout, vars)
+	}
+	if dir := filepath.Dir(output); dir != "." {
+		if err := fs.CreateDirectoryIfNotExists(dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := handlers.RenderTemplateToFile(fs, output, tmpl, vars); err != nil {
+		return nil, fmt.Errorf("rendering %s error: %s", output, err.Error())
+	}
+	style.CheckMarkPrint(fmt.Sprintf("file %s rendered successfully!", output))
+	return map[string]interface{}{"file": output}, nil
+}
+
+func init() {
+	Register(constants.RenderHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.RenderHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return HandleRender(module, params, vars)
+	}))
+}
@@ -0,0 +1,46 @@
+package execHandlers
+
+import (
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/spf13/afero"
+)
+
+func TestHandleRender(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, shared.KumaFilesPath+"/templates/service.go.tmpl",
+		[]byte(`package {{ .data.name }}{{ template "header" . }}`), 0644)
+	afero.WriteFile(memFs, shared.KumaFilesPath+"/templates/header.tmpl",
+		[]byte(`{{ define "header" }} // {{ .data.name | toUpper }}{{ end }}`), 0644)
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{"name": "users"},
+	}
+	result, err := HandleRender("", map[string]interface{}{
+		"template": "templates/service.go.tmpl",
+		"includes": []interface{}{"templates/header.tmpl"},
+		"output":   "{{ .data.name }}/service.go",
+	}, vars)
+	if err != nil {
+		t.Fatalf("HandleRender() error = %v", err)
+	}
+	if got := result["file"]; got != "users/service.go" {
+		t.Errorf("result file = %v, want %q", got, "users/service.go")
+	}
+	content, err := afero.ReadFile(memFs, "users/service.go")
+	if err != nil {
+		t.Fatalf("reading rendered file error: %v", err)
+	}
+	if want := "package users // USERS"; string(content) != want {
+		t.Errorf("rendered file = %q, want %q", content, want)
+	}
+
+	_, err = HandleRender("", map[string]interface{}{"template": "templates/missing.tmpl"}, vars)
+	if err == nil {
+		t.Errorf("HandleRender() with a missing template should fail")
+	}
+}
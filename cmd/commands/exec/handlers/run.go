@@ -1,31 +1,70 @@
 package execHandlers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	execFormHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers/form"
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
 	"github.com/arthurbcp/kuma/v2/cmd/shared"
 	"github.com/arthurbcp/kuma/v2/internal/domain"
 	"github.com/arthurbcp/kuma/v2/internal/services"
-	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/spf13/afero"
 )
 
-func HandleRun(name, moduleName string, vars map[string]interface{}) error {
+// stepModifiers are the step keys that change how a step is executed instead
+// of naming the handler that executes it.
+var stepModifiers = domain.StepModifiers
+
+// stepPolicy describes how a step is executed and what happens when its
+// handler fails.
+type stepPolicy struct {
+	name            string
+	register        string
+	skip            bool
+	continueOnError bool
+	retries         int
+	delay           time.Duration
+	onError         []interface{}
+}
+
+// ProjectRunPrefix is the module part of a reference to a run of the project,
+// e.g. "./:setup", usable from the runs of any module.
+const ProjectRunPrefix = "./"
+
+// runStack holds the references of the runs being executed, from the run
+// started from the command line to the innermost nested run.
+var runStack []string
+
+func HandleRun(ctx context.Context, name, moduleName string, vars map[string]interface{}) error {
 	var err error
 	var run = &domain.Run{}
-	fs := filesystem.NewFileSystem(afero.NewOsFs())
+	fs := shared.GetFileSystem()
+	name, moduleName = parseRunRef(name, moduleName)
+	if err := enterRun(runRef(name, moduleName)); err != nil {
+		return err
+	}
+	defer exitRun()
 	if moduleName != "" {
 		moduleService := services.NewModuleService(shared.KumaFilesPath, fs)
 		modules, err := moduleService.GetAll()
 		if err != nil {
 			return err
 		}
-		module := modules[moduleName]
+		module, ok := modules[moduleName]
+		if !ok {
+			return fmt.Errorf("module not found: %s", moduleName)
+		}
+		if _, ok := module.Runs[name]; !ok {
+			return fmt.Errorf("run not found: %s:%s", moduleName, name)
+		}
 		run, err = moduleService.GetRun(&module, name, shared.KumaFilesPath+"/"+moduleName+"/"+shared.KumaRunsPath)
 
 		if err != nil {
@@ -39,66 +78,366 @@ func HandleRun(name, moduleName string, vars map[string]interface{}) error {
 		}
 	}
 
-	for _, step := range run.Steps {
-		step := step.(map[string]interface{})
-		for key, value := range step {
-			switch key {
-			case constants.CmdHandler:
-				err := HandleCommand(value.(string), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.CmdHandler, err.Error())
-				}
-			case constants.LogHandler:
-				err := HandleLog(value.(string), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.LogHandler, err.Error())
-				}
-			case constants.RunHandler:
-				err := HandleRun(value.(string), moduleName, vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.RunHandler, err.Error())
-				}
-			case constants.CreateHandler:
-				err := HandleCreate(moduleName, value.(map[string]interface{}), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.CreateHandler, err.Error())
-				}
-			case constants.LoadHandler:
-				err := HandleLoad(value.(map[string]interface{}), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.LoadHandler, err.Error())
-				}
-			case constants.WhenHandler:
-				err := HandleWhen(moduleName, value.(map[string]interface{}), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.WhenHandler, err.Error())
-				}
-			case constants.ModifyHandler:
-				err := HandleModify(moduleName, value.(map[string]interface{}), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.ModifyHandler, err.Error())
-				}
-			case constants.FormHandler:
-				err := execFormHandlers.HandleForm(value.(map[string]interface{}), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.FormHandler, err.Error())
-				}
-			case constants.DefineHandler:
-				err := HandleDefine(value.(map[string]interface{}), vars)
-				if err != nil {
-					return fmt.Errorf("[handler: %s] - %s", constants.DefineHandler, err.Error())
-				}
-			default:
-				return fmt.Errorf("invalid handler type: %s", key)
+	runCtx := ctx
+	if run.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, run.Timeout)
+		defer cancel()
+	}
+	err = withStepPath(run.Key, func() error {
+		return handleRunSteps(runCtx, run, moduleName, vars)
+	})
+	if err != nil && ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+		return runTimeoutError{key: run.Key, timeout: run.Timeout}
+	}
+	return err
+}
+
+// parseRunRef splits a run reference into the run name and its module:
+// "module:run" refers to a run of a module, "./:run" to a run of the project
+// and a plain name to a run of the current module.
+func parseRunRef(ref string, moduleName string) (string, string) {
+	module, name, found := strings.Cut(ref, ":")
+	if !found {
+		return ref, moduleName
+	}
+	if module == ProjectRunPrefix || module == "." {
+		return name, ""
+	}
+	return name, module
+}
+
+// runRef returns the reference of a run as it is written in the runs of
+// other modules, e.g. "module:run".
+func runRef(name, moduleName string) string {
+	if moduleName == "" {
+		return name
+	}
+	return moduleName + ":" + name
+}
+
+// enterRun pushes a run to the run stack, failing when the run is already
+// being executed, which would never end, or when the stack is deeper than
+// the maximum depth.
+func enterRun(ref string) error {
+	for i, running := range runStack {
+		if running == ref {
+			cycle := append(append([]string{}, runStack[i:]...), ref)
+			return fmt.Errorf("run cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	if shared.MaxRunDepth > 0 && len(runStack) >= shared.MaxRunDepth {
+		return fmt.Errorf("maximum run depth of %d exceeded: %s -> %s",
+			shared.MaxRunDepth, strings.Join(runStack, " -> "), ref)
+	}
+	runStack = append(runStack, ref)
+	return nil
+}
+
+// exitRun pops the innermost run from the run stack.
+func exitRun() {
+	runStack = runStack[:len(runStack)-1]
+}
+
+// runTimeoutError is the error of a run stopped by its own timeout.
+type runTimeoutError struct {
+	key     string
+	timeout time.Duration
+}
+
+func (e runTimeoutError) Error() string {
+	return fmt.Sprintf("run %s timed out after %s", e.key, e.timeout)
+}
+
+func (e runTimeoutError) Unwrap() error {
+	return shared.ErrTimedOut
+}
+
+// stopError describes why the context of a run was stopped.
+func stopError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return shared.ErrTimedOut
+	}
+	return shared.ErrInterrupted
+}
+
+// handleRunSteps validates the inputs of a run and executes its steps
+// followed by its finally block. The finally block is executed even when the
+// run was interrupted or timed out, so that it can clean up.
+func handleRunSteps(ctx context.Context, run *domain.Run, moduleName string, vars map[string]interface{}) error {
+	if err := handleInputs(ctx, run, vars); err != nil {
+		return err
+	}
+
+	err := HandleSteps(ctx, run.Steps, moduleName, vars)
+	if len(run.Finally) > 0 {
+		saved := savedVars()
+		finallyErr := handleNestedSteps(context.WithoutCancel(ctx), "finally", run.Finally, moduleName, vars)
+		// the finally block must run again when the run is resumed
+		restoreSteps(currentStepPath()+"/finally", saved)
+		if finallyErr != nil {
+			if err != nil {
+				return fmt.Errorf("%w\n[finally] - %w", err, finallyErr)
+			}
+			return fmt.Errorf("[finally] - %w", finallyErr)
+		}
+	}
+	return err
+}
+
+// HandleSteps executes a list of steps in order, stopping at the first step
+// that fails according to its error policy or when ctx is canceled. Steps
+// completed by a resumed run are skipped.
+func HandleSteps(ctx context.Context, steps []interface{}, moduleName string, vars map[string]interface{}) error {
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			return stopError(ctx)
+		}
+		stepMap, ok := step.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid step: %v", step)
+		}
+		err := withStepPath(strconv.Itoa(i), func() error {
+			path := currentStepPath()
+			if isStepCompleted(path) {
+				style.LogPrint("skipping completed step " + path)
+				return nil
 			}
+			if err := handleStep(ctx, stepMap, moduleName, vars); err != nil {
+				return err
+			}
+			return completeStep(path)
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// handleNestedSteps executes a list of steps nested inside the current step,
+// e.g. the then branch of a when, identified by label in the step path.
+func handleNestedSteps(ctx context.Context, label string, steps []interface{}, moduleName string, vars map[string]interface{}) error {
+	return withStepPath(label, func() error {
+		return HandleSteps(ctx, steps, moduleName, vars)
+	})
+}
+
+// handleRunOrSteps executes the named run when it is set, or the inline steps
+// otherwise.
+func handleRunOrSteps(ctx context.Context, label string, run string, steps []interface{}, moduleName string, vars map[string]interface{}) error {
+	if run != "" {
+		return withStepPath(label, func() error {
+			return HandleRun(ctx, run, moduleName, vars)
+		})
+	}
+	return handleNestedSteps(ctx, label, steps, moduleName, vars)
+}
+
+// handleStep executes a single step applying its name, if, retry, on-error,
+// continue-on-error and register modifiers.
+func handleStep(ctx context.Context, step map[string]interface{}, moduleName string, vars map[string]interface{}) error {
+	policy, err := buildStepPolicy(step, vars)
+	if err != nil {
+		return err
+	}
+	if policy.skip {
+		if policy.name != "" {
+			style.LogPrint("skipping step " + policy.name)
+		}
+		registerResult(policy, skippedResult(), vars)
+		return nil
+	}
+	if policy.name != "" {
+		style.LogPrint(policy.name)
+	}
+
+	saved := savedVars()
+	result, err := dispatchStep(ctx, step, moduleName, vars)
+	if err != nil && policy.name != "" {
+		err = fmt.Errorf("[step: %s] - %w", policy.name, err)
+	}
+	if err != nil && ctx.Err() != nil {
+		// an interrupted step is neither retried nor handled
+		return err
+	}
+	for attempt := 1; err != nil && attempt <= policy.retries; attempt++ {
+		style.ErrorPrint(err.Error())
+		style.LogPrint(fmt.Sprintf("retrying in %s (%d/%d)...", policy.delay, attempt, policy.retries))
+		select {
+		case <-time.After(policy.delay):
+		case <-ctx.Done():
+			return err
+		}
+		restoreSteps(currentStepPath(), saved)
+		result, err = dispatchStep(ctx, step, moduleName, vars)
+		if err != nil && policy.name != "" {
+			err = fmt.Errorf("[step: %s] - %w", policy.name, err)
+		}
+		if err != nil && ctx.Err() != nil {
+			return err
+		}
+	}
+	if err == nil {
+		registerResult(policy, result, vars)
+		return nil
+	}
+	registerResult(policy, failedResult(result, err), vars)
+
+	if len(policy.onError) > 0 {
+		style.ErrorPrint(err.Error())
+		vars["error"] = err.Error()
+		saved := savedVars()
+		onErrorErr := handleNestedSteps(ctx, constants.OnErrorModifier, policy.onError, moduleName, vars)
+		delete(vars, "error")
+		if onErrorErr == nil {
+			return nil
+		}
+		// the step failed, so its on-error steps must run again on resume
+		restoreSteps(currentStepPath()+"/"+constants.OnErrorModifier, saved)
+		err = fmt.Errorf("%w\n[%s] - %w", err, constants.OnErrorModifier, onErrorErr)
+	}
+
+	if policy.continueOnError {
+		style.ErrorPrint(err.Error())
+		style.LogPrint("continuing after error...")
+		return nil
+	}
+	return err
+}
+
+// registerResult stores the result of a step in the .data variable named by
+// its register modifier.
+func registerResult(policy stepPolicy, result interface{}, vars map[string]interface{}) {
+	if policy.register == "" {
+		return
+	}
+	vars["data"].(map[string]interface{})[policy.register] = result
+}
+
+// skippedResult is the result of a step that was not executed, because its
+// if was false or because it would have run a command in dry-run mode.
+func skippedResult() map[string]interface{} {
+	return map[string]interface{}{"skipped": true}
+}
+
+// failedResult adds the error of a failed step to its result, so that the
+// steps handling the error can inspect both.
+func failedResult(result interface{}, err error) map[string]interface{} {
+	failed := map[string]interface{}{}
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		for key, value := range resultMap {
+			failed[key] = value
+		}
+	}
+	failed["error"] = err.Error()
+	return failed
+}
+
+func buildStepPolicy(step map[string]interface{}, vars map[string]interface{}) (stepPolicy, error) {
+	var err error
+	policy := stepPolicy{}
+
+	policy.name, err = execBuilders.BuildStringValue(constants.NameModifier, step, vars, false, constants.NameModifier)
+	if err != nil {
+		return policy, err
+	}
+	policy.register, err = execBuilders.BuildStringValue(constants.RegisterModifier, step, vars, false, constants.RegisterModifier)
+	if err != nil {
+		return policy, err
+	}
+
+	if _, ok := step[constants.IfModifier]; ok {
+		run, err := execBuilders.BuildBoolValue(constants.IfModifier, step, vars, false, constants.IfModifier)
+		if err != nil {
+			return policy, err
+		}
+		policy.skip = !run
+	}
+
+	policy.continueOnError, err = execBuilders.BuildBoolValue(constants.ContinueOnErrorModifier, step, vars, false, constants.ContinueOnErrorModifier)
+	if err != nil {
+		return policy, err
+	}
+
+	if retry, ok := step[constants.RetryModifier]; ok {
+		retryMap, ok := retry.(map[string]interface{})
+		if !ok {
+			return policy, fmt.Errorf("%s must be a map with times and delay", constants.RetryModifier)
+		}
+		policy.retries, err = execBuilders.BuildIntValue("times", retryMap, vars, true, constants.RetryModifier)
+		if err != nil {
+			return policy, err
+		}
+		policy.delay, err = execBuilders.BuildDurationValue("delay", retryMap, vars, false, constants.RetryModifier)
+		if err != nil {
+			return policy, err
+		}
+	}
+
+	if onError, ok := step[constants.OnErrorModifier]; ok {
+		policy.onError, ok = onError.([]interface{})
+		if !ok {
+			return policy, fmt.Errorf("%s must be a list of steps", constants.OnErrorModifier)
+		}
+	}
+	return policy, nil
+}
+
+// dispatchStep executes the handler of a step, ignoring its modifiers, and
+// returns the result of the handler.
+func dispatchStep(ctx context.Context, step map[string]interface{}, moduleName string, vars map[string]interface{}) (interface{}, error) {
+	key, err := stepHandlerKey(step)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := resolveHandler(moduleName, key)
+	if err != nil {
+		return nil, err
+	}
+	result, err := handler.Handle(ctx, moduleName, step[key], vars)
+	if err != nil {
+		return result, fmt.Errorf("[handler: %s] - %w", key, err)
+	}
+	return result, nil
+}
+
+// stepHandlerKey returns the only key of a step that is not a modifier.
+// Run files are validated when they are loaded, so this only fails for steps
+// that were not read from a run file.
+func stepHandlerKey(step map[string]interface{}) (string, error) {
+	keys := []string{}
+	for key := range step {
+		if !stepModifiers[key] {
+			keys = append(keys, key)
+		}
+	}
+	switch len(keys) {
+	case 0:
+		return "", fmt.Errorf("step has no handler")
+	case 1:
+		return keys[0], nil
+	}
+	sort.Strings(keys)
+	return "", fmt.Errorf("step has more than one handler: %s", strings.Join(keys, ", "))
+}
+
 func ExitCLI(tprogram *tea.Program) {
 	if err := tprogram.ReleaseTerminal(); err != nil {
 		log.Fatal(err)
 	}
 	os.Exit(1)
 }
+
+func init() {
+	Register(constants.RunHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		if params, ok := value.(map[string]interface{}); ok {
+			return HandleRunCall(ctx, module, params, vars)
+		}
+		name, err := stringParam(constants.RunHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, HandleRun(ctx, name, module, vars)
+	}))
+}
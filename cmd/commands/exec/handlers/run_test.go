@@ -0,0 +1,452 @@
+package execHandlers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/domain"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/spf13/afero"
+)
+
+func TestParseRunRef(t *testing.T) {
+	testCases := []struct {
+		ref, module          string
+		wantName, wantModule string
+	}{
+		{ref: "setup", module: "", wantName: "setup", wantModule: ""},
+		{ref: "setup", module: "go", wantName: "setup", wantModule: "go"},
+		{ref: "lint:setup-lint", module: "go", wantName: "setup-lint", wantModule: "lint"},
+		{ref: "lint:setup-lint", module: "", wantName: "setup-lint", wantModule: "lint"},
+		{ref: "./:setup", module: "go", wantName: "setup", wantModule: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ref, func(t *testing.T) {
+			name, module := parseRunRef(tc.ref, tc.module)
+			if name != tc.wantName || module != tc.wantModule {
+				t.Errorf("parseRunRef() = %q, %q, want %q, %q", name, module, tc.wantName, tc.wantModule)
+			}
+		})
+	}
+}
+
+func TestHandleRun_CrossModule(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	files := map[string]string{
+		shared.KumaRunsPath + "/main.yaml": `
+main:
+  steps:
+    - define: {variable: trail, value: "main"}
+    - run: lint:setup-lint
+project-helper:
+  visible: false
+  steps:
+    - define: {variable: trail, value: "{{ .data.trail }} > project-helper"}
+`,
+		shared.KumaFilesPath + "/kuma-modules.yaml": `
+lint:
+  description: lint
+  version: v1
+  runs:
+    setup-lint: {file: lint.yaml}
+`,
+		shared.KumaFilesPath + "/lint/" + shared.KumaRunsPath + "/lint.yaml": `
+setup-lint:
+  steps:
+    - define: {variable: trail, value: "{{ .data.trail }} > setup-lint"}
+    - run: ./:project-helper
+    - run: missing:setup
+`,
+	}
+	for path, content := range files {
+		afero.WriteFile(memFs, path, []byte(content), 0644)
+	}
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	err := HandleRun(context.Background(), "main", "", vars)
+	if err == nil || err.Error() != "[handler: run] - [handler: run] - module not found: missing" {
+		t.Errorf("HandleRun() error = %v, want module not found", err)
+	}
+	if got := vars["data"].(map[string]interface{})["trail"]; got != "main > setup-lint > project-helper" {
+		t.Errorf("trail = %q, want %q", got, "main > setup-lint > project-helper")
+	}
+}
+
+func TestHandleRunCall(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, shared.KumaRunsPath+"/endpoint.yaml", []byte(`
+create-endpoint:
+  inputs:
+    name: {required: true}
+    method: {default: GET}
+  steps:
+    - define: {variable: route, value: "{{ .data.method }} /{{ .data.name }}"}
+    - define: {variable: count, value: "{{ len .data.api.paths }} paths"}
+    - define: {variable: temp, value: "leaks"}
+`), 0644)
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{
+		"data": map[string]interface{}{
+			"name":   "caller",
+			"prefix": "users",
+			"api":    map[string]interface{}{"paths": map[string]interface{}{"/users": nil}},
+		},
+	}
+	steps := []interface{}{
+		map[string]interface{}{"run": map[string]interface{}{
+			"name":    "create-endpoint",
+			"with":    map[string]interface{}{"name": "{{ .data.prefix }}", "api": "{{ .data.api }}"},
+			"outputs": []interface{}{"route", "count"},
+		}},
+	}
+	if err := HandleSteps(context.Background(), steps, "", vars); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+
+	data := vars["data"].(map[string]interface{})
+	if data["route"] != "GET /users" || data["count"] != "1 paths" || data["name"] != "caller" {
+		t.Errorf("data = %v, want route and count copied back and name untouched", data)
+	}
+	if _, ok := data["temp"]; ok {
+		t.Errorf("temp was copied back without being an output")
+	}
+
+	_, err := HandleRunCall(context.Background(), "", map[string]interface{}{
+		"name":    "create-endpoint",
+		"with":    map[string]interface{}{"name": "orders", "api": map[string]interface{}{"paths": []interface{}{}}},
+		"outputs": []interface{}{"missing"},
+	}, vars)
+	if err == nil || err.Error() != "run create-endpoint did not set the output missing" {
+		t.Errorf("HandleRunCall() error = %v, want missing output", err)
+	}
+	_, err = HandleRunCall(context.Background(), "", map[string]interface{}{"name": "create-endpoint"}, vars)
+	if err == nil {
+		t.Errorf("HandleRunCall() without the required input should fail")
+	}
+	_, err = HandleRunCall(context.Background(), "", map[string]interface{}{
+		"name": "create-endpoint",
+		"with": map[string]interface{}{"name": "{{ .data.missing }}"},
+	}, vars)
+	if err == nil || err.Error() != "parsing with name error: variable not found: data.missing" {
+		t.Errorf("HandleRunCall() error = %v, want the missing with variable", err)
+	}
+}
+
+func TestHandleSteps_Register(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, shared.KumaFilesPath+"/templates/route.tmpl", []byte("router.Get(\"/{{ .data.name }}\")\n"), 0644)
+	afero.WriteFile(memFs, "routes.go", []byte("package routes\n// routes\n"), 0644)
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{"data": map[string]interface{}{"name": "users"}}
+	steps := []interface{}{
+		map[string]interface{}{"cmd": "echo hello", "register": "greeting"},
+		map[string]interface{}{"cmd": "sh -c 'exit 2'", "register": "failed", "continue-on-error": true},
+		map[string]interface{}{"log": "never", "if": "false", "register": "skipped"},
+		map[string]interface{}{"modify": map[string]interface{}{
+			"file":     "routes.go",
+			"template": "templates/route.tmpl",
+			"mark":     "// routes\n",
+			"action":   "insert-after",
+		}, "register": "routes"},
+		map[string]interface{}{"define": map[string]interface{}{
+			"variable": "summary",
+			"value":    "{{ .data.greeting.stdout }} {{ .data.failed.exitCode }} {{ .data.routes.changed }}",
+		}},
+	}
+	if err := HandleSteps(context.Background(), steps, "", vars); err != nil {
+		t.Fatalf("HandleSteps() error = %v", err)
+	}
+
+	data := vars["data"].(map[string]interface{})
+	if got := data["summary"]; got != "hello 2 true" {
+		t.Errorf("summary = %q, want %q", got, "hello 2 true")
+	}
+	if got := data["failed"].(map[string]interface{})["error"]; !strings.Contains(got.(string), "exit status 2") {
+		t.Errorf("failed error = %q, want the command error", got)
+	}
+	if got := data["skipped"]; !reflect.DeepEqual(got, map[string]interface{}{"skipped": true}) {
+		t.Errorf("skipped = %v, want skipped: true", got)
+	}
+	diff := data["routes"].(map[string]interface{})["diff"].(string)
+	if !strings.Contains(diff, "+router.Get(\"/users\")") {
+		t.Errorf("diff = %q, want the inserted line", diff)
+	}
+}
+
+func TestHandleRun_Cycles(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, shared.KumaRunsPath+"/runs.yaml", []byte(`
+init:
+  steps:
+    - run: setup
+setup:
+  steps:
+    - when:
+        condition: "true"
+        run: init
+chain:
+  steps:
+    - run: first
+first:
+  steps:
+    - run: second
+second:
+  steps:
+    - log: "done"
+`), 0644)
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	err := HandleRun(context.Background(), "init", "", vars)
+	if err == nil || !strings.Contains(err.Error(), "run cycle detected: init -> setup -> init") {
+		t.Errorf("HandleRun() error = %v, want the cycle", err)
+	}
+	if len(runStack) != 0 {
+		t.Errorf("run stack = %v, want it empty after the run", runStack)
+	}
+
+	defer func(depth int) { shared.MaxRunDepth = depth }(shared.MaxRunDepth)
+	shared.MaxRunDepth = 2
+	err = HandleRun(context.Background(), "chain", "", vars)
+	if err == nil || !strings.Contains(err.Error(), "maximum run depth of 2 exceeded: chain -> first -> second") {
+		t.Errorf("HandleRun() error = %v, want the maximum depth", err)
+	}
+	shared.MaxRunDepth = 3
+	if err := HandleRun(context.Background(), "chain", "", vars); err != nil {
+		t.Errorf("HandleRun() error = %v", err)
+	}
+}
+
+func TestHandleRun_Cancel(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, shared.KumaRunsPath+"/slow.yaml", []byte(`
+slow:
+  timeout: 100ms
+  steps:
+    - cmd: sleep 5
+      retry: {times: 3, delay: 1s}
+    - define: {variable: after, value: "never"}
+  finally:
+    - define: {variable: cleaned, value: "yes"}
+caller:
+  steps:
+    - name: call slow
+      run: slow
+`), 0644)
+	shared.SetFileSystem(filesystem.NewFileSystem(memFs))
+	defer shared.SetFileSystem(nil)
+
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	start := time.Now()
+	err := HandleRun(context.Background(), "slow", "", vars)
+	if err == nil || err.Error() != "run slow timed out after 100ms" {
+		t.Errorf("HandleRun() error = %v, want the run timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("HandleRun() took %s, want the command killed at the timeout", elapsed)
+	}
+	data := vars["data"].(map[string]interface{})
+	if _, ok := data["after"]; ok {
+		t.Errorf("a step after the timeout was executed")
+	}
+	if data["cleaned"] != "yes" {
+		t.Errorf("the finally block was not executed after the timeout")
+	}
+
+	err = HandleRun(context.Background(), "caller", "", vars)
+	if code := shared.ExitCode(context.Background(), err); code != shared.ExitCodeTimeout {
+		t.Errorf("ExitCode(%v) = %d, want %d", err, code, shared.ExitCodeTimeout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	steps := []interface{}{map[string]interface{}{"log": "never", "continue-on-error": true}}
+	err = HandleSteps(ctx, steps, "", vars)
+	if err == nil || err.Error() != "run interrupted" {
+		t.Errorf("HandleSteps() error = %v, want run interrupted", err)
+	}
+	if code := shared.ExitCode(ctx, err); code != shared.ExitCodeInterrupted {
+		t.Errorf("ExitCode(%v) = %d, want %d", err, code, shared.ExitCodeInterrupted)
+	}
+
+	afero.WriteFile(memFs, shared.KumaRunsPath+"/invalid.yaml", []byte(`
+invalid:
+  timeout: 1.5
+  steps:
+    - log: never
+`), 0644)
+	err = HandleRun(context.Background(), "invalid", "", vars)
+	if err == nil || !strings.Contains(err.Error(), "run invalid: invalid timeout 1.5") {
+		t.Errorf("HandleRun() error = %v, want the invalid timeout", err)
+	}
+}
+
+func TestHandleStep_Policy(t *testing.T) {
+	// flaky fails the first time it runs in dir and succeeds afterwards
+	flaky := func(dir string) string {
+		return "test -f " + dir + "/flag || { touch " + dir + "/flag; exit 1; }"
+	}
+	// counted appends a line to dir/count every time it runs and fails
+	counted := func(dir string) string {
+		return "echo x >> " + dir + "/count; exit 1"
+	}
+	testCases := []struct {
+		name    string
+		step    func(dir string) map[string]interface{}
+		wantErr string
+		check   func(t *testing.T, dir string, vars map[string]interface{}, elapsed time.Duration)
+	}{
+		{
+			name:    "Fails without a policy",
+			step:    func(dir string) map[string]interface{} { return map[string]interface{}{"cmd": "false"} },
+			wantErr: "exit status 1",
+		},
+		{
+			name: "Retry until it succeeds",
+			step: func(dir string) map[string]interface{} {
+				return map[string]interface{}{
+					"cmd":   map[string]interface{}{"command": flaky(dir), "shell": true},
+					"retry": map[string]interface{}{"times": 2},
+				}
+			},
+		},
+		{
+			name: "Retry exhausted with delay",
+			step: func(dir string) map[string]interface{} {
+				return map[string]interface{}{
+					"name":  "counted",
+					"cmd":   map[string]interface{}{"command": counted(dir), "shell": true},
+					"retry": map[string]interface{}{"times": 2, "delay": "30ms"},
+				}
+			},
+			wantErr: "[step: counted] - [handler: cmd]",
+			check: func(t *testing.T, dir string, vars map[string]interface{}, elapsed time.Duration) {
+				content, _ := afero.ReadFile(afero.NewOsFs(), dir+"/count")
+				if got := strings.Count(string(content), "x"); got != 3 {
+					t.Errorf("attempts = %d, want 3", got)
+				}
+				if elapsed < 60*time.Millisecond {
+					t.Errorf("elapsed = %s, want at least two delays of 30ms", elapsed)
+				}
+			},
+		},
+		{
+			name: "On error handles the failure",
+			step: func(dir string) map[string]interface{} {
+				return map[string]interface{}{
+					"cmd": "false",
+					"on-error": []interface{}{
+						map[string]interface{}{"define": map[string]interface{}{"variable": "caught", "value": "{{ .error }}"}},
+					},
+				}
+			},
+			check: func(t *testing.T, dir string, vars map[string]interface{}, elapsed time.Duration) {
+				caught, _ := vars["data"].(map[string]interface{})["caught"].(string)
+				if !strings.Contains(caught, "exit status 1") {
+					t.Errorf("caught = %q, want the step error", caught)
+				}
+				if _, ok := vars["error"]; ok {
+					t.Errorf("error = %v, want it removed after on-error", vars["error"])
+				}
+			},
+		},
+		{
+			name: "On error that fails",
+			step: func(dir string) map[string]interface{} {
+				return map[string]interface{}{
+					"cmd":      "false",
+					"on-error": []interface{}{map[string]interface{}{"cmd": "sh -c 'exit 4'"}},
+				}
+			},
+			wantErr: "[on-error] - [handler: cmd] - command error: exit status 4",
+		},
+		{
+			name: "Continue on error",
+			step: func(dir string) map[string]interface{} {
+				return map[string]interface{}{"cmd": "false", "continue-on-error": true, "register": "failed"}
+			},
+			check: func(t *testing.T, dir string, vars map[string]interface{}, elapsed time.Duration) {
+				failed := vars["data"].(map[string]interface{})["failed"].(map[string]interface{})
+				if failed["exitCode"] != 1 {
+					t.Errorf("failed = %v, want exitCode 1", failed)
+				}
+			},
+		},
+		{
+			name:    "Invalid retry",
+			step:    func(dir string) map[string]interface{} { return map[string]interface{}{"cmd": "true", "retry": 2} },
+			wantErr: "retry must be a map with times and delay",
+		},
+		{
+			name: "Invalid on error",
+			step: func(dir string) map[string]interface{} {
+				return map[string]interface{}{"cmd": "true", "on-error": "log"}
+			},
+			wantErr: "on-error must be a list of steps",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			vars := map[string]interface{}{"data": map[string]interface{}{}}
+			start := time.Now()
+			err := handleStep(context.Background(), tc.step(dir), "", vars)
+			elapsed := time.Since(start)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("handleStep() error = %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("handleStep() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+			if tc.check != nil {
+				tc.check(t, dir, vars, elapsed)
+			}
+		})
+	}
+}
+
+func TestHandleRunSteps_Finally(t *testing.T) {
+	run := &domain.Run{
+		Key: "deploy",
+		Steps: []interface{}{
+			map[string]interface{}{"define": map[string]interface{}{"variable": "started", "value": "yes"}},
+			map[string]interface{}{"cmd": "false"},
+			map[string]interface{}{"define": map[string]interface{}{"variable": "after", "value": "never"}},
+		},
+		Finally: []interface{}{
+			map[string]interface{}{"define": map[string]interface{}{"variable": "cleaned", "value": "{{ .data.started }}"}},
+		},
+	}
+	vars := map[string]interface{}{"data": map[string]interface{}{}}
+	err := handleRunSteps(context.Background(), run, "", vars)
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+		t.Fatalf("handleRunSteps() error = %v, want the step error", err)
+	}
+	data := vars["data"].(map[string]interface{})
+	if _, ok := data["after"]; ok {
+		t.Errorf("a step after the failure was executed")
+	}
+	if data["cleaned"] != "yes" {
+		t.Errorf("cleaned = %v, want the finally block executed after the failure", data["cleaned"])
+	}
+
+	run.Steps = []interface{}{map[string]interface{}{"log": "ok"}}
+	run.Finally = []interface{}{map[string]interface{}{"cmd": "false"}}
+	err = handleRunSteps(context.Background(), run, "", vars)
+	if err == nil || !strings.HasPrefix(err.Error(), "[finally] - ") {
+		t.Errorf("handleRunSteps() error = %v, want the finally error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package execHandlers
+
+import (
+	"context"
+	"fmt"
+
+	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
+	"github.com/arthurbcp/kuma/v2/cmd/constants"
+	"github.com/arthurbcp/kuma/v2/internal/functions"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+)
+
+func HandleSwitch(ctx context.Context, module string, params map[string]interface{}, vars map[string]interface{}) error {
+	value, err := switchValue(params, vars, constants.SwitchHandler)
+	if err != nil {
+		return err
+	}
+	cases, ok := params["cases"].([]interface{})
+	if !ok {
+		return fmt.Errorf("cases is required for %s", constants.SwitchHandler)
+	}
+
+	for _, c := range cases {
+		caseMap, ok := c.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid case: %v", c)
+		}
+		caseValue, err := switchValue(caseMap, vars, constants.SwitchCaseComponent)
+		if err != nil {
+			return err
+		}
+		if caseValue != value {
+			continue
+		}
+		run, err := execBuilders.BuildStringValue("run", caseMap, vars, false, constants.SwitchCaseComponent)
+		if err != nil {
+			return err
+		}
+		steps, hasSteps := caseMap["steps"].([]interface{})
+		if run != "" && hasSteps {
+			return fmt.Errorf("run and steps cannot be used together in a %s", constants.SwitchCaseComponent)
+		}
+		if err := handleRunOrSteps(ctx, "case-"+caseValue, run, steps, module, vars); err != nil {
+			return fmt.Errorf("[case: %s] - %w", caseValue, err)
+		}
+		return nil
+	}
+
+	defaultSteps, _ := params["default"].([]interface{})
+	return handleNestedSteps(ctx, "default", defaultSteps, module, vars)
+}
+
+// switchValue renders the value of a switch or of one of its cases as a
+// string, so that numbers and booleans compare with their text.
+func switchValue(input map[string]interface{}, vars map[string]interface{}, component string) (string, error) {
+	value, ok := input["value"]
+	if !ok || value == nil {
+		return "", fmt.Errorf("value is required for %s", component)
+	}
+	value, err := helpers.ReplaceVarsInValue(value, vars, functions.GetFuncMap())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(value), nil
+}
+
+func init() {
+	Register(constants.SwitchHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.SwitchHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, HandleSwitch(ctx, module, params, vars)
+	}))
+}
@@ -0,0 +1,58 @@
+package execHandlers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHandleSwitch(t *testing.T) {
+	branch := func(value string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"define": map[string]interface{}{"variable": "branch", "value": value}},
+		}
+	}
+	cases := []interface{}{
+		map[string]interface{}{"value": "rest", "steps": branch("rest")},
+		map[string]interface{}{"value": 3, "steps": branch("three")},
+		map[string]interface{}{"value": true, "steps": branch("bool")},
+	}
+	testCases := []struct {
+		name    string
+		params  map[string]interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "String case", params: map[string]interface{}{"value": "{{ .data.kind }}", "cases": cases}, want: "rest"},
+		{name: "Number case", params: map[string]interface{}{"value": "{{ .data.count }}", "cases": cases}, want: "three"},
+		{name: "Bool case", params: map[string]interface{}{"value": true, "cases": cases}, want: "bool"},
+		{name: "Default", params: map[string]interface{}{"value": "grpc", "cases": cases, "default": branch("default")}, want: "default"},
+		{name: "No match without default", params: map[string]interface{}{"value": "grpc", "cases": cases}, want: nil},
+		{name: "Missing value", params: map[string]interface{}{"cases": cases}, wantErr: "value is required for switch"},
+		{
+			name: "Run and steps",
+			params: map[string]interface{}{"value": "rest", "cases": []interface{}{
+				map[string]interface{}{"value": "rest", "run": "setup", "steps": branch("rest")},
+			}},
+			wantErr: "run and steps cannot be used together in a case",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars := map[string]interface{}{"data": map[string]interface{}{"kind": "rest", "count": 3}}
+			err := HandleSwitch(context.Background(), "", tc.params, vars)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("HandleSwitch() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleSwitch() error = %v", err)
+			}
+			if got := vars["data"].(map[string]interface{})["branch"]; got != tc.want {
+				t.Errorf("branch = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
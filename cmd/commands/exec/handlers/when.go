@@ -1,25 +1,43 @@
 package execHandlers
 
 import (
+	"context"
+	"fmt"
+
 	execBuilders "github.com/arthurbcp/kuma/v2/cmd/commands/exec/builders"
 	"github.com/arthurbcp/kuma/v2/cmd/constants"
 )
 
-func HandleWhen(module string, params map[string]interface{}, vars map[string]interface{}) error {
+func HandleWhen(ctx context.Context, module string, params map[string]interface{}, vars map[string]interface{}) error {
 	isTrue, err := execBuilders.BuildBoolValue("condition", params, vars, true, constants.WhenHandler)
 	if err != nil {
 		return err
 	}
-	run, err := execBuilders.BuildStringValue("run", params, vars, true, constants.WhenHandler)
+	run, err := execBuilders.BuildStringValue("run", params, vars, false, constants.WhenHandler)
 	if err != nil {
 		return err
 	}
+	thenSteps, hasThen := params["then"].([]interface{})
+	elseSteps, hasElse := params["else"].([]interface{})
+	if run == "" && !hasThen && !hasElse {
+		return fmt.Errorf("run, then or else is required for %s", constants.WhenHandler)
+	}
+	if run != "" && hasThen {
+		return fmt.Errorf("run and then cannot be used together in %s", constants.WhenHandler)
+	}
+
 	if isTrue {
-		err := HandleRun(run, module, vars)
-		if err != nil {
-			return err
-		}
+		return handleRunOrSteps(ctx, "then", run, thenSteps, module, vars)
 	}
+	return handleNestedSteps(ctx, "else", elseSteps, module, vars)
+}
 
-	return nil
+func init() {
+	Register(constants.WhenHandler, HandlerFunc(func(ctx context.Context, module string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+		params, err := mapParam(constants.WhenHandler, value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, HandleWhen(ctx, module, params, vars)
+	}))
 }
@@ -0,0 +1,45 @@
+package execHandlers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHandleWhen(t *testing.T) {
+	branch := func(value string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"define": map[string]interface{}{"variable": "branch", "value": value}},
+		}
+	}
+	testCases := []struct {
+		name    string
+		params  map[string]interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "Then", params: map[string]interface{}{"condition": "{{ eq .data.env \"prod\" }}", "then": branch("then"), "else": branch("else")}, want: "then"},
+		{name: "Else", params: map[string]interface{}{"condition": "{{ eq .data.env \"dev\" }}", "then": branch("then"), "else": branch("else")}, want: "else"},
+		{name: "False without else", params: map[string]interface{}{"condition": "false", "then": branch("then")}, want: nil},
+		{name: "Missing branches", params: map[string]interface{}{"condition": "true"}, wantErr: "run, then or else is required for when"},
+		{name: "Run and then", params: map[string]interface{}{"condition": "true", "run": "setup", "then": branch("then")}, wantErr: "run and then cannot be used together in when"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars := map[string]interface{}{"data": map[string]interface{}{"env": "prod"}}
+			err := HandleWhen(context.Background(), "", tc.params, vars)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("HandleWhen() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleWhen() error = %v", err)
+			}
+			if got := vars["data"].(map[string]interface{})["branch"]; got != tc.want {
+				t.Errorf("branch = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
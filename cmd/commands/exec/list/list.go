@@ -0,0 +1,115 @@
+package execList
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/services"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+var (
+	// module lists the runs of an installed module instead of the project runs.
+	module string
+	// output is the format of the list, table or json.
+	output string
+)
+
+// List the runs available in the project or in a module
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the runs of the project or of a module",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := List(shared.GetFileSystem(), module)
+		if err != nil {
+			style.ErrorPrint("listing runs error: " + err.Error())
+			os.Exit(1)
+		}
+		if err := Print(os.Stdout, entries, output); err != nil {
+			style.ErrorPrint("listing runs error: " + err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// Entry is a run as shown by the list command.
+type Entry struct {
+	Key         string `json:"key"`
+	Module      string `json:"module,omitempty"`
+	Description string `json:"description"`
+	File        string `json:"file"`
+	Visible     bool   `json:"visible"`
+}
+
+// List returns every run of the project or, when module is set, of that
+// installed module, hidden runs included and sorted by key.
+func List(fs filesystem.FileSystemInterface, module string) ([]Entry, error) {
+	path := shared.KumaRunsPath
+	if module != "" {
+		modules, err := services.NewModuleService(shared.KumaFilesPath, fs).GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("getting modules error: %s", err.Error())
+		}
+		if _, ok := modules[module]; !ok {
+			return nil, fmt.Errorf("module not found: %s", module)
+		}
+		path = shared.KumaFilesPath + "/" + module + "/" + shared.KumaRunsPath
+	}
+	runs, err := services.NewRunService(path, fs).GetAll(false)
+	if err != nil {
+		return nil, fmt.Errorf("getting runs error: %s", err.Error())
+	}
+	entries := make([]Entry, 0, len(runs))
+	for key, run := range runs {
+		entries = append(entries, Entry{
+			Key:         key,
+			Module:      module,
+			Description: run.Description,
+			File:        run.File,
+			Visible:     run.Visible,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+// Print writes the entries to w as a table or as a JSON array.
+func Print(w io.Writer, entries []Entry, format string) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case OutputTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "RUN\tDESCRIPTION\tFILE\tVISIBLE")
+		for _, entry := range entries {
+			description := strings.ReplaceAll(entry.Description, "\n", " ")
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", entry.Key, description, entry.File, entry.Visible)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid output format %s, use %s or %s", format, OutputTable, OutputJSON)
+	}
+}
+
+func init() {
+	ListCmd.Flags().StringVarP(&module, "module", "m", "", "list the runs of an installed module")
+	ListCmd.Flags().StringVarP(&output, "output", "o", OutputTable, "output format, table or json")
+}
@@ -0,0 +1,104 @@
+package execList
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/spf13/afero"
+)
+
+func TestList(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	files := map[string]string{
+		shared.KumaRunsPath + "/main.yaml": `
+setup:
+  description: Set up the project
+  steps:
+    - log: setup
+helper:
+  visible: false
+  steps:
+    - log: helper
+`,
+		shared.KumaFilesPath + "/kuma-modules.yaml": `
+lint:
+  description: lint
+  version: v1
+  runs:
+    setup-lint: {file: lint.yaml}
+`,
+		shared.KumaFilesPath + "/lint/" + shared.KumaRunsPath + "/lint.yaml": `
+setup-lint:
+  description: Set up the linter
+  steps:
+    - log: lint
+`,
+	}
+	for path, content := range files {
+		afero.WriteFile(memFs, path, []byte(content), 0644)
+	}
+	fs := filesystem.NewFileSystem(memFs)
+
+	entries, err := List(fs, "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []Entry{
+		{Key: "helper", File: "main.yaml", Visible: false},
+		{Key: "setup", Description: "Set up the project", File: "main.yaml", Visible: true},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("List() = %+v, want %+v", entries, want)
+	}
+
+	entries, err = List(fs, "lint")
+	if err != nil {
+		t.Fatalf("List(lint) error = %v", err)
+	}
+	want = []Entry{
+		{Key: "setup-lint", Module: "lint", Description: "Set up the linter", File: "lint.yaml", Visible: true},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("List(lint) = %+v, want %+v", entries, want)
+	}
+
+	if _, err := List(fs, "missing"); err == nil || err.Error() != "module not found: missing" {
+		t.Errorf("List(missing) error = %v, want module not found", err)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	entries := []Entry{
+		{Key: "setup", Description: "Set up the project", File: "main.yaml", Visible: true},
+	}
+
+	var table bytes.Buffer
+	if err := Print(&table, entries, OutputTable); err != nil {
+		t.Fatalf("Print(table) error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "RUN") || !strings.HasPrefix(lines[1], "setup") {
+		t.Errorf("Print(table) = %q, want a header and one row", table.String())
+	}
+
+	var out bytes.Buffer
+	if err := Print(&out, entries, OutputJSON); err != nil {
+		t.Fatalf("Print(json) error = %v", err)
+	}
+	var got []Entry
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Print(json) = %q, not valid JSON: %v", out.String(), err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("Print(json) = %+v, want %+v", got, entries)
+	}
+
+	if err := Print(&out, entries, "xml"); err == nil {
+		t.Error("Print(xml) error = nil, want invalid output format")
+	}
+}
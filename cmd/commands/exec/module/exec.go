@@ -1,6 +1,7 @@
 package execModule
 
 import (
+	"context"
 	"os"
 
 	execHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers"
@@ -21,22 +22,32 @@ var ExecModuleCmd = &cobra.Command{
 	Use:   "module",
 	Short: "Execute a specific run from a module",
 	Run: func(cmd *cobra.Command, args []string) {
-		Execute()
+		Execute(cmd.Context())
 	},
 }
 
-func Execute() {
-	if shared.Run == "" || shared.Module == "" {
+// Execute runs the selected run, stopping it when ctx is canceled. It exits
+// with shared.ExitCodeInterrupted when the run is interrupted and with
+// shared.ExitCodeTimeout when it runs out of time.
+func Execute(ctx context.Context) {
+	if (shared.Run == "" || shared.Module == "") && !shared.Resume {
 		shared.Run = handleTea()
 	}
-	vars := map[string]interface{}{
-		"data": map[string]interface{}{},
+	ctx, cancel := shared.WithRunTimeout(ctx)
+	defer cancel()
+	err := execHandlers.ExecuteRun(ctx, shared.Run, shared.Module)
+	if err != nil {
+		style.ErrorPrint(err.Error())
+		os.Exit(shared.ExitCode(ctx, err))
 	}
-	execHandlers.HandleRun(shared.Run, shared.Module, vars)
 }
 
 func handleTea() string {
 	var err error
+	if shared.NoInput {
+		style.ErrorPrint("--module and --run are required when --no-input is set")
+		os.Exit(1)
+	}
 	program := program.NewProgram()
 
 	fs := filesystem.NewFileSystem(afero.NewOsFs())
@@ -69,6 +80,9 @@ func handleTea() string {
 
 		shared.Module = output.Choice
 	}
+	if shared.Run != "" {
+		return shared.Run
+	}
 
 	runService := services.NewRunService(shared.KumaFilesPath+"/"+shared.Module+"/"+shared.KumaRunsPath, fs)
 	runs, err := runService.GetAll(true)
@@ -83,7 +97,7 @@ func handleTea() string {
 			key,
 			run.Description,
 			[]string{},
-		))
+		).WithGroup(run.File))
 	}
 
 	output := &selectInput.Selection{}
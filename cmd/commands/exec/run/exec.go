@@ -1,6 +1,7 @@
 package execRun
 
 import (
+	"context"
 	"os"
 
 	execHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers"
@@ -21,26 +22,32 @@ var ExecCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Execute a specific run without a module",
 	Run: func(cmd *cobra.Command, args []string) {
-		Execute()
+		Execute(cmd.Context())
 	},
 }
 
-func Execute() {
-	if shared.Run == "" {
+// Execute runs the selected run, stopping it when ctx is canceled. It exits
+// with shared.ExitCodeInterrupted when the run is interrupted and with
+// shared.ExitCodeTimeout when it runs out of time.
+func Execute(ctx context.Context) {
+	if shared.Run == "" && !shared.Resume {
 		shared.Run = handleTea()
 	}
-	vars := map[string]interface{}{
-		"data": map[string]interface{}{},
-	}
-	err := execHandlers.HandleRun(shared.Run, "", vars)
+	ctx, cancel := shared.WithRunTimeout(ctx)
+	defer cancel()
+	err := execHandlers.ExecuteRun(ctx, shared.Run, "")
 	if err != nil {
 		style.ErrorPrint(err.Error())
-		os.Exit(1)
+		os.Exit(shared.ExitCode(ctx, err))
 	}
 }
 
 func handleTea() string {
 	var err error
+	if shared.NoInput {
+		style.ErrorPrint("--run is required when --no-input is set")
+		os.Exit(1)
+	}
 	program := program.NewProgram()
 
 	fs := filesystem.NewFileSystem(afero.NewOsFs())
@@ -57,7 +64,7 @@ func handleTea() string {
 			key,
 			run.Description,
 			[]string{},
-		))
+		).WithGroup(run.File))
 	}
 
 	output := &selectInput.Selection{}
@@ -1,6 +1,7 @@
 package modify
 
 import (
+	"context"
 	"net/url"
 	"os"
 	"strings"
@@ -39,11 +40,11 @@ var ModifyCmd = &cobra.Command{
 	Use:   "modify",
 	Short: "Modify a scaffold for a project based on Go Templates",
 	Run: func(cmd *cobra.Command, args []string) {
-		Modify()
+		Modify(cmd.Context())
 	},
 }
 
-func Modify() {
+func Modify(ctx context.Context) {
 	fs := filesystem.NewFileSystem(afero.NewOsFs())
 	if VariablesFile != "" {
 		var vars interface{}
@@ -56,7 +57,7 @@ func Modify() {
 			}
 		} else {
 			style.LogPrint("downloading variables file")
-			varsContent, err := shared.ReadFileFromURL(VariablesFile)
+			varsContent, err := shared.ReadFileFromURL(ctx, VariablesFile)
 			if err != nil {
 				style.ErrorPrint("reading file error: " + err.Error())
 				os.Exit(1)
@@ -1,6 +1,7 @@
 package module
 
 import (
+	"context"
 	"os"
 
 	execModule "github.com/arthurbcp/kuma/v2/cmd/commands/exec/module"
@@ -36,12 +37,16 @@ func handleTea() string {
 	var options = make([]steps.Item, 0)
 
 	for repository, template := range shared.Templates {
-		options = append(options, steps.NewItem(
+		item := steps.NewItem(
 			template.Name,
 			repository,
 			template.Description,
 			template.Tags,
-		))
+		)
+		if len(template.Tags) > 0 {
+			item = item.WithGroup(template.Tags[0])
+		}
+		options = append(options, item)
 	}
 
 	output := &selectInput.Selection{}
@@ -73,7 +78,7 @@ func download(cmd *cobra.Command) {
 		os.Exit(1)
 	}
 
-	err = addGitSubmodule(Repository)
+	err = addGitSubmodule(cmd.Context(), Repository)
 	if err != nil {
 		style.ErrorPrint("error adding submodule: " + err.Error())
 		os.Exit(1)
@@ -88,14 +93,14 @@ func download(cmd *cobra.Command) {
 		os.Exit(1)
 	}
 	shared.Module = moduleName
-	execModule.Execute()
+	execModule.Execute(cmd.Context())
 	os.Exit(0)
 }
 
-func addGitSubmodule(module string) error {
+func addGitSubmodule(ctx context.Context, module string) error {
 	fs := filesystem.NewFileSystem(afero.NewOsFs())
 	moduleService := services.NewModuleService(shared.KumaFilesPath, fs)
-	if err := shared.RunCommand("git", "submodule", "add", shared.GitHubURL+"/"+module, shared.KumaFilesPath+"/"+moduleService.GetModuleName(module)); err != nil {
+	if err := shared.RunCommand(ctx, "git", "submodule", "add", shared.GitHubURL+"/"+module, shared.KumaFilesPath+"/"+moduleService.GetModuleName(module)); err != nil {
 		return err
 	}
 	return nil
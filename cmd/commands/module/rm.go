@@ -1,6 +1,7 @@
 package module
 
 import (
+	"context"
 	"os"
 
 	"github.com/arthurbcp/kuma/v2/cmd/shared"
@@ -22,7 +23,7 @@ var ModuleRmCmd = &cobra.Command{
 	Short: "Remove a Kuma module",
 	Run: func(cmd *cobra.Command, args []string) {
 		if Module != "" {
-			err := RemoveModule(Module)
+			err := RemoveModule(cmd.Context(), Module)
 			if err != nil {
 				style.ErrorPrint("error removing module: " + err.Error())
 				os.Exit(1)
@@ -31,9 +32,9 @@ var ModuleRmCmd = &cobra.Command{
 	},
 }
 
-func RemoveModule(module string) error {
+func RemoveModule(ctx context.Context, module string) error {
 	if RemoveGitSubmodule {
-		if err := removeGitSubmodule(module); err != nil {
+		if err := removeGitSubmodule(ctx, module); err != nil {
 			return err
 		}
 	}
@@ -47,22 +48,22 @@ func RemoveModule(module string) error {
 }
 
 // remove git submodule removes a submodule from Kuma
-func removeGitSubmodule(module string) error {
+func removeGitSubmodule(ctx context.Context, module string) error {
 	// Full path to the submodule inside .kuma
 	fullSubmodulePath := shared.KumaFilesPath + "/" + module
 
 	// 1. Remove submodule config from .git/config
-	if err := shared.RunCommand("git", "config", "--remove-section", "submodule."+fullSubmodulePath); err != nil {
+	if err := shared.RunCommand(ctx, "git", "config", "--remove-section", "submodule."+fullSubmodulePath); err != nil {
 		return err
 	}
 
 	// 2. Remove submodule entry from .gitmodules if it exists
-	if err := shared.RunCommand("git", "config", "-f", ".gitmodules", "--remove-section", "submodule."+fullSubmodulePath); err != nil {
+	if err := shared.RunCommand(ctx, "git", "config", "-f", ".gitmodules", "--remove-section", "submodule."+fullSubmodulePath); err != nil {
 		return err
 	}
 
 	// 3. Remove the submodule from git cache
-	if err := shared.RunCommand("git", "rm", "--cached", fullSubmodulePath); err != nil {
+	if err := shared.RunCommand(ctx, "git", "rm", "--cached", fullSubmodulePath); err != nil {
 		return err
 	}
 
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/internal/handlers"
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	TemplateFile string
+
+	OutputFile string
+
+	VariablesFile string
+
+	Includes []string
+)
+
+// Render a single template to a file or to the standard output
+var RenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render a single Go template to a file or to the standard output",
+	Run: func(cmd *cobra.Command, args []string) {
+		Render(cmd.Context())
+	},
+}
+
+func Render(ctx context.Context) {
+	fs := filesystem.NewFileSystem(afero.NewOsFs())
+	toStdout := OutputFile == "" || OutputFile == "-"
+	vars := map[string]interface{}{}
+	if VariablesFile != "" {
+		var err error
+		vars, err = readVariables(ctx, fs, VariablesFile, toStdout)
+		if err != nil {
+			style.ErrorPrint("parsing file error: " + err.Error())
+			os.Exit(1)
+		}
+	}
+
+	includes := []interface{}{}
+	for _, include := range Includes {
+		includes = append(includes, include)
+	}
+	tmpl, err := handlers.ParseTemplate(fs, ".", map[string]interface{}{
+		"template": TemplateFile,
+		"includes": includes,
+	})
+	if err != nil {
+		style.ErrorPrint(err.Error())
+		os.Exit(1)
+	}
+
+	data := map[string]interface{}{"data": vars}
+	if toStdout {
+		err = tmpl.Execute(os.Stdout, data)
+	} else {
+		if dir := filepath.Dir(OutputFile); dir != "." {
+			err = fs.CreateDirectoryIfNotExists(dir)
+		}
+		if err == nil {
+			err = handlers.RenderTemplateToFile(fs, OutputFile, tmpl, data)
+		}
+	}
+	if err != nil {
+		style.ErrorPrint("rendering template error: " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// readVariables reads the variables from a local file or an URL. A quiet
+// download prints nothing, so that the standard output only holds the
+// rendered template.
+func readVariables(ctx context.Context, fs *filesystem.FileSystem, variablesFile string, quiet bool) (map[string]interface{}, error) {
+	if parsed, err := url.ParseRequestURI(variablesFile); err != nil || parsed.Scheme == "" {
+		return helpers.UnmarshalFile(variablesFile, fs)
+	}
+	var varsContent string
+	var err error
+	if quiet {
+		varsContent, err = shared.DownloadFile(ctx, variablesFile)
+	} else {
+		style.LogPrint("downloading variables file")
+		varsContent, err = shared.ReadFileFromURL(ctx, variablesFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	splitURL := strings.Split(variablesFile, "/")
+	return helpers.UnmarshalByExt(splitURL[len(splitURL)-1], []byte(varsContent))
+}
+
+// init sets up flags for the 'render' subcommand and binds them to variables.
+func init() {
+	RenderCmd.Flags().StringVarP(&TemplateFile, "template", "t", "", "Path to the template file")
+	RenderCmd.Flags().StringVarP(&OutputFile, "output", "o", "", "Path to the rendered file, the standard output when omitted")
+	RenderCmd.Flags().StringVarP(&VariablesFile, "variables", "v", "", "path or URL to the variables file")
+	RenderCmd.Flags().StringArrayVarP(&Includes, "include", "i", []string{}, "Path to a template file with definitions used by the template")
+	RenderCmd.MarkFlagRequired("template")
+}
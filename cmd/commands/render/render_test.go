@@ -0,0 +1,42 @@
+package render
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRender_StdoutWithURLVariables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("name: users\n"))
+	}))
+	defer server.Close()
+
+	wd, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	defer os.Chdir(wd)
+	os.WriteFile("service.tmpl", []byte("service {{ .data.name }}\n"), 0644)
+
+	TemplateFile, VariablesFile, OutputFile = "service.tmpl", server.URL+"/vars.yaml", ""
+	defer func() {
+		TemplateFile, VariablesFile = "", ""
+	}()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	Render(context.Background())
+	os.Stdout = stdout
+	writer.Close()
+
+	output, _ := io.ReadAll(reader)
+	if string(output) != "service users\n" {
+		t.Errorf("Render() output = %q, want only the rendered template", output)
+	}
+}
@@ -0,0 +1,56 @@
+package state
+
+import (
+	"os"
+
+	execFormHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers/form"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+// answersOnly makes the clear command remove only the remembered answers.
+var answersOnly bool
+
+// Manage the state kept by kuma for the project
+var StateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage the state kept in " + shared.KumaStatePath,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// Remove the state kept by kuma for the project
+var StateClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the remembered answers, the journal of the last failed run and the undo record",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := Clear(answersOnly); err != nil {
+			style.ErrorPrint("clearing state error: " + err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// Clear removes every state file of the project or, with answersOnly, only
+// the remembered answers.
+func Clear(answersOnly bool) error {
+	if answersOnly {
+		if err := shared.RemoveStateFile(execFormHandlers.AnswersFile); err != nil {
+			return err
+		}
+		style.CheckMarkPrint("remembered answers cleared")
+		return nil
+	}
+	if err := shared.ClearState(); err != nil {
+		return err
+	}
+	style.CheckMarkPrint("state cleared")
+	return nil
+}
+
+func init() {
+	StateClearCmd.Flags().BoolVarP(&answersOnly, "answers", "", false, "only remove the remembered answers")
+	StateCmd.AddCommand(StateClearCmd)
+}
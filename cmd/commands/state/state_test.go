@@ -0,0 +1,43 @@
+package state
+
+import (
+	"os"
+	"testing"
+
+	execFormHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers/form"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestClear(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, name := range []string{execFormHandlers.AnswersFile, "journal.yaml"} {
+		if err := shared.WriteStateFile(name, []byte("state")); err != nil {
+			t.Fatalf("WriteStateFile() error = %v", err)
+		}
+	}
+
+	if err := Clear(true); err != nil {
+		t.Fatalf("Clear(answers) error = %v", err)
+	}
+	if _, err := shared.ReadStateFile(execFormHandlers.AnswersFile); !os.IsNotExist(err) {
+		t.Errorf("the remembered answers were not removed, error = %v", err)
+	}
+	if _, err := shared.ReadStateFile("journal.yaml"); err != nil {
+		t.Errorf("the journal was removed with --answers, error = %v", err)
+	}
+	if err := Clear(true); err != nil {
+		t.Errorf("Clear(answers) without answers error = %v", err)
+	}
+
+	if err := Clear(false); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, err := os.Stat(shared.KumaStatePath); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed, error = %v", shared.KumaStatePath, err)
+	}
+}
@@ -0,0 +1,42 @@
+package undo
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+// Revert the files changed by the last transactional run
+var UndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the files changed by the last run executed with --transaction",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := Undo(); err != nil {
+			style.ErrorPrint("undo error: " + err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// Undo restores the files saved by the last transactional run and removes
+// its undo record, so the same run can not be reverted twice.
+func Undo() error {
+	snapshot, err := shared.LoadUndo()
+	if err != nil {
+		return err
+	}
+	if err := shared.RestoreSnapshot(snapshot); err != nil {
+		return err
+	}
+	for _, file := range snapshot.Files {
+		if file.Existed {
+			style.CheckMarkPrint(fmt.Sprintf("%s restored", file.Path))
+		} else {
+			style.CheckMarkPrint(fmt.Sprintf("%s removed", file.Path))
+		}
+	}
+	return shared.RemoveStateFile(shared.UndoFile)
+}
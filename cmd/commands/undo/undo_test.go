@@ -0,0 +1,70 @@
+package undo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	execHandlers "github.com/arthurbcp/kuma/v2/cmd/commands/exec/handlers"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+)
+
+func TestUndo(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	shared.Transactional = true
+	shared.SetFileSystem(nil)
+	defer func() {
+		shared.Transactional = false
+		shared.SetFileSystem(nil)
+	}()
+
+	// transactional runs stage the files they write
+	if err := exec.Command("git", "init", "--quiet").Run(); err != nil {
+		t.Fatalf("git init error: %v", err)
+	}
+	files := map[string]string{
+		shared.KumaRunsPath + "/setup.yaml": `
+setup:
+  steps:
+    - modify: {file: main.go, template: route.tmpl, mark: "// routes"}
+    - file: {action: copy, from: main.go, to: cmd/main.go}
+    - file: {action: delete, path: old.txt}
+`,
+		shared.KumaFilesPath + "/route.tmpl": "// routes\nroute()\n",
+		"main.go":                            "package main\n// routes\n",
+		"old.txt":                            "old\n",
+	}
+	for path, content := range files {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	if err := execHandlers.ExecuteRun(context.Background(), "setup", ""); err != nil {
+		t.Fatalf("ExecuteRun() error = %v", err)
+	}
+	if content, _ := os.ReadFile("main.go"); string(content) == files["main.go"] {
+		t.Fatalf("main.go was not modified by the run")
+	}
+
+	if err := Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	for _, path := range []string{"main.go", "old.txt"} {
+		if content, _ := os.ReadFile(path); string(content) != files[path] {
+			t.Errorf("%s = %q after undo, want %q", path, content, files[path])
+		}
+	}
+	if _, err := os.Stat("cmd"); !os.IsNotExist(err) {
+		t.Errorf("cmd was kept after undo, want it removed")
+	}
+
+	if err := Undo(); err == nil {
+		t.Errorf("Undo() twice error = nil, want no run to undo")
+	}
+}
@@ -1,15 +1,34 @@
 package constants
 
+import "github.com/arthurbcp/kuma/v2/internal/domain"
+
+// The keys of the handlers and modifiers are defined by the domain, which
+// validates the steps of the run files.
+const (
+	CreateHandler = domain.CreateHandler
+	LoadHandler   = domain.LoadHandler
+	LogHandler    = domain.LogHandler
+	RunHandler    = domain.RunHandler
+	WhenHandler   = domain.WhenHandler
+	ModifyHandler = domain.ModifyHandler
+	CmdHandler    = domain.CmdHandler
+	FormHandler   = domain.FormHandler
+	DefineHandler = domain.DefineHandler
+	EachHandler   = domain.EachHandler
+	SwitchHandler = domain.SwitchHandler
+	HttpHandler   = domain.HttpHandler
+	AssertHandler = domain.AssertHandler
+	FileHandler   = domain.FileHandler
+	RenderHandler = domain.RenderHandler
+)
+
 const (
-	CreateHandler = "create"
-	LoadHandler   = "load"
-	LogHandler    = "log"
-	RunHandler    = "run"
-	WhenHandler   = "when"
-	ModifyHandler = "modify"
-	CmdHandler    = "cmd"
-	FormHandler   = "form"
-	DefineHandler = "define"
+	NameModifier            = domain.NameModifier
+	IfModifier              = domain.IfModifier
+	ContinueOnErrorModifier = domain.ContinueOnErrorModifier
+	RetryModifier           = domain.RetryModifier
+	OnErrorModifier         = domain.OnErrorModifier
+	RegisterModifier        = domain.RegisterModifier
 )
 
 const (
@@ -21,4 +40,5 @@ const (
 	SelectComponent            = "select"
 	MultiSelectOptionComponent = "multi-select-option"
 	SelectOptionComponent      = "select-option"
+	SwitchCaseComponent        = "case"
 )
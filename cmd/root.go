@@ -8,6 +8,10 @@ import (
 	execRun "github.com/arthurbcp/kuma/v2/cmd/commands/exec"
 	"github.com/arthurbcp/kuma/v2/cmd/commands/modify"
 	"github.com/arthurbcp/kuma/v2/cmd/commands/module"
+	"github.com/arthurbcp/kuma/v2/cmd/commands/render"
+	"github.com/arthurbcp/kuma/v2/cmd/commands/state"
+	"github.com/arthurbcp/kuma/v2/cmd/commands/undo"
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
 	"github.com/arthurbcp/kuma/v2/internal/debug"
 	"github.com/spf13/cobra"
 )
@@ -26,8 +30,11 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// Execute runs the command line with a context canceled by Ctrl+C.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, cancel := shared.NewCommandContext()
+	defer cancel()
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -40,4 +47,7 @@ func init() {
 	rootCmd.AddCommand(module.ModuleCmd)
 	rootCmd.AddCommand(execRun.ExecCmd)
 	rootCmd.AddCommand(modify.ModifyCmd)
+	rootCmd.AddCommand(undo.UndoCmd)
+	rootCmd.AddCommand(render.RenderCmd)
+	rootCmd.AddCommand(state.StateCmd)
 }
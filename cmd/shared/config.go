@@ -1,13 +1,28 @@
 package shared
 
+import "time"
+
 var (
 	KumaFilesPath string = ".kuma"
 
 	KumaRunsPath string = KumaFilesPath + "/runs"
 
+	KumaStatePath string = KumaFilesPath + "/.state"
+
 	OfficialTemplatesPath string = "official-templates.yaml"
 
 	Run string
 
 	Module string
+
+	// Resume continues the last failed run from the journal.
+	Resume bool
+
+	// MaxRunDepth is the number of runs that can be nested inside each
+	// other, set with --max-depth. Zero disables the limit.
+	MaxRunDepth int = DefaultMaxRunDepth
+
+	// Timeout stops the run when it takes longer, set with --timeout. Zero
+	// means no timeout.
+	Timeout time.Duration
 )
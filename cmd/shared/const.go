@@ -2,4 +2,15 @@ package shared
 
 const (
 	GitHubURL = "https://github.com"
+
+	// DefaultMaxRunDepth is the default value of --max-depth.
+	DefaultMaxRunDepth = 32
+
+	// ExitCodeInterrupted is the exit code of a command interrupted with
+	// Ctrl+C, as for shells.
+	ExitCodeInterrupted = 130
+
+	// ExitCodeTimeout is the exit code of a run stopped by --timeout, as for
+	// the timeout command.
+	ExitCodeTimeout = 124
 )
@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var (
+	// ErrInterrupted is the error of a run stopped by Ctrl+C or SIGTERM.
+	ErrInterrupted = errors.New("run interrupted")
+	// ErrTimedOut is the error of a run stopped by --timeout or by the
+	// timeout of the run.
+	ErrTimedOut = errors.New("run timed out")
+)
+
+// interrupt cancels the context of the running command, as Ctrl+C does.
+var interrupt context.CancelFunc = func() {}
+
+// NewCommandContext returns the context of a command, which is canceled by
+// Ctrl+C or SIGTERM. After the first signal the default behaviour is
+// restored, so a second Ctrl+C stops kuma right away even when the cleanup
+// of the command hangs.
+func NewCommandContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	interrupt = cancel
+	return ctx, cancel
+}
+
+// Interrupt cancels the context of the running command, for interruptions
+// that do not arrive as a signal, e.g. Ctrl+C inside a form.
+func Interrupt() {
+	interrupt()
+}
+
+// WithRunTimeout bounds a context with the --timeout of the run, if any.
+func WithRunTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if Timeout > 0 {
+		return context.WithTimeout(ctx, Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// ExitCode returns the exit code of a command that failed with err:
+// ExitCodeInterrupted when it was interrupted, ExitCodeTimeout when it ran
+// out of time and 1 otherwise. Errors that do not tell why the run stopped,
+// e.g. a command killed by the cancellation, fall back to ctx.
+func ExitCode(ctx context.Context, err error) int {
+	switch {
+	case errors.Is(err, ErrInterrupted):
+		return ExitCodeInterrupted
+	case errors.Is(err, ErrTimedOut):
+		return ExitCodeTimeout
+	}
+	switch ctx.Err() {
+	case context.Canceled:
+		return ExitCodeInterrupted
+	case context.DeadlineExceeded:
+		return ExitCodeTimeout
+	}
+	return 1
+}
@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"github.com/spf13/afero"
+)
+
+var (
+	// DryRun makes every handler work on an in-memory overlay of the project
+	// and records commands instead of running them.
+	DryRun bool
+
+	// PlannedCommands holds the commands skipped while DryRun is enabled.
+	PlannedCommands []string
+
+	// Transactional snapshots every file a run changes, so that the changes
+	// are rolled back when the run fails. It has no effect in dry-run mode.
+	Transactional bool
+
+	baseFileSystem *filesystem.FileSystem
+	fileSystem     filesystem.FileSystemInterface
+	transaction    *filesystem.Transaction
+)
+
+// GetFileSystem returns the file system shared by every step of a run, so
+// files written by one step are visible to the next ones even in dry-run mode.
+func GetFileSystem() filesystem.FileSystemInterface {
+	if fileSystem == nil {
+		if DryRun {
+			SetFileSystem(filesystem.NewDryRunFileSystem(afero.NewOsFs()))
+		} else {
+			SetFileSystem(filesystem.NewFileSystem(afero.NewOsFs()))
+		}
+	}
+	return fileSystem
+}
+
+// SetFileSystem replaces the file system shared by the steps of a run, e.g.
+// with an in-memory one in tests. A nil fs makes GetFileSystem create a new
+// one.
+func SetFileSystem(fs *filesystem.FileSystem) {
+	if fs == nil {
+		baseFileSystem, fileSystem, transaction = nil, nil, nil
+		return
+	}
+	baseFileSystem = fs
+	fileSystem = fs
+	transaction = nil
+	if Transactional && !DryRun {
+		transaction = filesystem.NewTransaction(fs)
+		fileSystem = transaction
+	}
+}
+
+// GetTransaction returns the transaction of the shared file system, or nil
+// when the run is not transactional.
+func GetTransaction() *filesystem.Transaction {
+	GetFileSystem()
+	return transaction
+}
+
+// PrintDryRunPlan prints the files and commands a dry run would have touched.
+func PrintDryRunPlan() error {
+	GetFileSystem()
+	created, modified, err := baseFileSystem.Changes()
+	if err != nil {
+		return fmt.Errorf("listing dry run changes error: %s", err.Error())
+	}
+	style.TitlePrint("dry run: nothing was written, staged or executed", false)
+	printPlanSection("files that would be created", "+ ", created)
+	printPlanSection("files that would be changed", "~ ", modified)
+	printPlanSection("files that would be deleted", "- ", baseFileSystem.Deleted())
+	printPlanSection("commands that would run", "$ ", PlannedCommands)
+	return nil
+}
+
+func printPlanSection(title, prefix string, items []string) {
+	if len(items) == 0 {
+		style.LogPrint(title + ": none")
+		return
+	}
+	style.LogPrint(title + ":")
+	fmt.Println("  " + prefix + strings.Join(items, "\n  "+prefix) + "\n")
+}
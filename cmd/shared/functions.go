@@ -1,48 +1,78 @@
 package shared
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"strings"
 
-	"github.com/arthurbcp/kuma/v2/pkg/style"
 	"github.com/charmbracelet/huh/spinner"
 )
 
-func RunCommand(command string, args ...string) error {
-	cmd := exec.Command(command, args...)
+// RunCommand executes a command attached to the terminal. The command is
+// killed when ctx is canceled.
+func RunCommand(ctx context.Context, command string, args ...string) error {
+	if DryRun {
+		PlannedCommands = append(PlannedCommands, strings.Join(append([]string{command}, args...), " "))
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, command, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-func ReadFileFromURL(url string) (string, error) {
+// ReadFileFromURL downloads a file while showing a spinner. The download is
+// stopped when ctx is canceled.
+func ReadFileFromURL(ctx context.Context, url string) (string, error) {
 	var bodyBytes []byte
+	var downloadErr error
 	err := spinner.New().
 		Title("Downloading variables file").
 		Action(func() {
-			resp, err := http.Get(url)
-			if err != nil {
-				style.ErrorPrint("downloading variables file error: " + err.Error())
-				os.Exit(1)
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				style.ErrorPrint(fmt.Sprintf("bad status: %s", resp.Status))
-				os.Exit(1)
-			}
-
-			bodyBytes, err = io.ReadAll(resp.Body)
-			if err != nil {
-				style.ErrorPrint("reading file error: " + err.Error())
-				os.Exit(1)
-			}
+			bodyBytes, downloadErr = download(ctx, url)
 		}).Run()
 	if err != nil {
 		return "", err
 	}
+	if downloadErr != nil {
+		return "", downloadErr
+	}
+	return string(bodyBytes), nil
+}
+
+// DownloadFile downloads a file without printing anything, for commands
+// whose standard output is their result. The download is stopped when ctx is
+// canceled.
+func DownloadFile(ctx context.Context, url string) (string, error) {
+	bodyBytes, err := download(ctx, url)
+	if err != nil {
+		return "", err
+	}
 	return string(bodyBytes), nil
 }
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloading variables file error: %s", err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading variables file error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading file error: %s", err.Error())
+	}
+	return bodyBytes, nil
+}
@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// SetValues holds the key=value pairs given with --set.
+	SetValues []string
+
+	// AnswersFile is the JSON or YAML file given with --answers.
+	AnswersFile string
+
+	// RecordFile is the file where --record saves every form answer.
+	RecordFile string
+
+	// ReplayFile is the file of answers given with --replay.
+	ReplayFile string
+
+	// NoInput makes runs fail instead of opening an interactive prompt.
+	NoInput bool
+
+	preset = map[string]interface{}{}
+)
+
+// BuildData returns the initial .data of a run, pre-filled with the values of
+// the answers file and then with the --set values. Keys can be dotted paths,
+// e.g. --set api.name=users.
+func BuildData() (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	if AnswersFile != "" {
+		answers, err := helpers.UnmarshalFile(AnswersFile, GetFileSystem())
+		if err != nil {
+			return nil, fmt.Errorf("reading answers file error: %s", err.Error())
+		}
+		for key, value := range answers {
+			data[key] = value
+		}
+	}
+	for _, set := range SetValues {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set value %q, expected key=value", set)
+		}
+		helpers.SetByPath(data, key, parseSetValue(value))
+	}
+	preset = data
+	return helpers.CopyMap(data), nil
+}
+
+// parseSetValue reads a --set value as a YAML scalar, the same way values are
+// read from the answers file, so that --set confirm=false is a bool and
+// --set replicas=3 is a number. Numbers that would not print back the same,
+// e.g. 1.10 or 007, and any other value are kept as strings.
+func parseSetValue(value string) interface{} {
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+	switch parsed.(type) {
+	case bool:
+		return parsed
+	case int, float64:
+		if fmt.Sprint(parsed) == value {
+			return parsed
+		}
+	}
+	return value
+}
+
+// PresetValue returns the value of a variable given with --set or --answers.
+// Nested maps are copied, so the preset is not changed by the run.
+func PresetValue(key string) (interface{}, bool) {
+	value, ok := helpers.GetByPath(preset, key)
+	if valueMap, isMap := value.(map[string]interface{}); isMap {
+		value = helpers.CopyMap(valueMap)
+	}
+	return value, ok
+}
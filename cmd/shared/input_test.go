@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildData(t *testing.T) {
+	defer func() { SetValues = nil }()
+	SetValues = []string{
+		"name=users",
+		"confirm=false",
+		"replicas=3",
+		"ratio=0.5",
+		"api.version=v1",
+		"empty=",
+		"list=[a, b]",
+		"query=a=b",
+		"version=1.10",
+		"zip=007",
+	}
+	data, err := BuildData()
+	if err != nil {
+		t.Fatalf("BuildData() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"name":     "users",
+		"confirm":  false,
+		"replicas": 3,
+		"ratio":    0.5,
+		"api":      map[string]interface{}{"version": "v1"},
+		"empty":    "",
+		"list":     "[a, b]",
+		"query":    "a=b",
+		"version":  "1.10",
+		"zip":      "007",
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("BuildData() = %#v, want %#v", data, want)
+	}
+	if value, ok := PresetValue("api.version"); !ok || value != "v1" {
+		t.Errorf("PresetValue(api.version) = %v, %v, want v1", value, ok)
+	}
+	if _, ok := PresetValue("missing"); ok {
+		t.Errorf("PresetValue(missing) found a value")
+	}
+
+	SetValues = []string{"=value"}
+	if _, err := BuildData(); err == nil {
+		t.Errorf("BuildData() should fail without a key")
+	}
+}
@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// stateFs is where the state files are kept. They are never part of the
+// generated project, so they bypass the dry-run overlay and git staging.
+var stateFs afero.Fs = afero.NewOsFs()
+
+// WriteStateFile writes a file inside the state directory, which is created
+// with a .gitignore so that its content is never committed.
+func WriteStateFile(name string, content []byte) error {
+	if err := stateFs.MkdirAll(KumaStatePath, os.ModePerm); err != nil {
+		return err
+	}
+	gitignore := KumaStatePath + "/.gitignore"
+	exists, err := afero.Exists(stateFs, gitignore)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := afero.WriteFile(stateFs, gitignore, []byte("*\n"), 0644); err != nil {
+			return err
+		}
+	}
+	return afero.WriteFile(stateFs, KumaStatePath+"/"+name, content, 0644)
+}
+
+// ReadStateFile reads a file from the state directory.
+func ReadStateFile(name string) ([]byte, error) {
+	return afero.ReadFile(stateFs, KumaStatePath+"/"+name)
+}
+
+// RemoveStateFile removes a file from the state directory, if it exists.
+func RemoveStateFile(name string) error {
+	err := stateFs.Remove(KumaStatePath + "/" + name)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ClearState removes the state directory with every state file in it.
+func ClearState() error {
+	return stateFs.RemoveAll(KumaStatePath)
+}
@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// UndoFile is the state file with the original files of the last
+// transactional run.
+const UndoFile = "undo.yaml"
+
+// SaveUndo saves the snapshot of a transactional run so that `kuma undo`
+// can revert it later. Runs that changed nothing leave the previous record.
+func SaveUndo(snapshot filesystem.Snapshot) error {
+	if len(snapshot.Files) == 0 && len(snapshot.Dirs) == 0 {
+		return nil
+	}
+	content, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encoding undo record error: %s", err.Error())
+	}
+	if err := WriteStateFile(UndoFile, content); err != nil {
+		return fmt.Errorf("writing undo record error: %s", err.Error())
+	}
+	return nil
+}
+
+// LoadUndo reads the snapshot saved by the last transactional run.
+func LoadUndo() (filesystem.Snapshot, error) {
+	snapshot := filesystem.Snapshot{}
+	content, err := ReadStateFile(UndoFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshot, fmt.Errorf("there is no run to undo, only runs executed with --transaction can be undone")
+		}
+		return snapshot, fmt.Errorf("reading undo record error: %s", err.Error())
+	}
+	if err := yaml.Unmarshal(content, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("parsing undo record error: %s", err.Error())
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot restores the original files of a snapshot in the project
+// and unstages the files it removes, which were staged when they were written.
+func RestoreSnapshot(snapshot filesystem.Snapshot) error {
+	if err := snapshot.Restore(afero.NewOsFs()); err != nil {
+		return err
+	}
+	for _, file := range snapshot.Files {
+		if file.Existed {
+			continue
+		}
+		// outside of a git repository there is nothing to unstage
+		_ = exec.Command("git", "rm", "--cached", "--quiet", "--ignore-unmatch", file.Path).Run()
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package selectInput
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/arthurbcp/kuma/v2/cmd/ui/utils/steps"
+)
+
+// labelBonus ranks items whose label matches the filter above items
+// that only match by description or tags.
+const labelBonus = 100
+
+// fuzzyScore reports whether every character of pattern appears in text in
+// the same order, ignoring case and spaces. Matches that are consecutive or
+// start a word score higher.
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, true
+	}
+	score, pi, prev := 0, 0, -2
+	for i, r := range t {
+		if pi == len(p) {
+			break
+		}
+		if r != p[pi] {
+			continue
+		}
+		score++
+		if prev == i-1 {
+			score += 5
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 10
+		}
+		prev = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// matchItem scores an item against the filter, matching its label first and
+// then its label, description and tags together.
+func matchItem(pattern string, item steps.Item) (int, bool) {
+	if score, ok := fuzzyScore(pattern, item.Label); ok {
+		return score + labelBonus, true
+	}
+	return fuzzyScore(pattern, item.Keywords)
+}
+
+// sortItems orders the items by group and then by label, so the options
+// are listed the same way on every launch.
+func sortItems(items []steps.Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Group != items[j].Group {
+			return items[i].Group < items[j].Group
+		}
+		return items[i].Label < items[j].Label
+	})
+}
+
+// filterItems returns the indexes of the items that match the pattern. The
+// items keep their group, best matches first inside each group.
+func filterItems(items []steps.Item, pattern string) []int {
+	indexes := make([]int, 0, len(items))
+	scores := make(map[int]int)
+	for i, item := range items {
+		score, ok := matchItem(pattern, item)
+		if !ok {
+			continue
+		}
+		indexes = append(indexes, i)
+		scores[i] = score
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		ia, ib := indexes[a], indexes[b]
+		if items[ia].Group != items[ib].Group {
+			return items[ia].Group < items[ib].Group
+		}
+		return scores[ia] > scores[ib]
+	})
+	return indexes
+}
@@ -0,0 +1,99 @@
+package selectInput
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arthurbcp/kuma/v2/cmd/ui/utils/program"
+	"github.com/arthurbcp/kuma/v2/cmd/ui/utils/steps"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          bool
+	}{
+		{"", "anything", true},
+		{"svc", "new-service", true},
+		{"NS", "new service", true},
+		{"new svc", "new-service", true},
+		{"cvs", "new-service", false},
+		{"services", "service", false},
+	}
+	for _, tc := range tests {
+		if _, ok := fuzzyScore(tc.pattern, tc.text); ok != tc.want {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tc.pattern, tc.text, ok, tc.want)
+		}
+	}
+
+	consecutive, _ := fuzzyScore("ser", "service")
+	scattered, _ := fuzzyScore("ser", "sub-order")
+	if consecutive <= scattered {
+		t.Errorf("fuzzyScore consecutive = %d, scattered = %d, want consecutive higher", consecutive, scattered)
+	}
+}
+
+func TestFilterItems(t *testing.T) {
+	items := []steps.Item{
+		steps.NewItem("lint", "lint", "Set up the linter", []string{}).WithGroup("tools.yaml"),
+		steps.NewItem("service", "service", "Create a service", []string{"api"}).WithGroup("api.yaml"),
+		steps.NewItem("handler", "handler", "Create a handler for a service", []string{}).WithGroup("api.yaml"),
+		steps.NewItem("client", "client", "", []string{"api"}).WithGroup("api.yaml"),
+	}
+	sortItems(items)
+
+	labels := func(indexes []int) []string {
+		got := []string{}
+		for _, i := range indexes {
+			got = append(got, items[i].Label)
+		}
+		return got
+	}
+	if got, want := labels(filterItems(items, "")), []string{"client", "handler", "service", "lint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterItems(\"\") = %v, want %v", got, want)
+	}
+	if got, want := labels(filterItems(items, "service")), []string{"service", "handler"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterItems(service) = %v, want %v", got, want)
+	}
+	if got, want := labels(filterItems(items, "api")), []string{"client", "service"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterItems(api) = %v, want %v", got, want)
+	}
+}
+
+func TestModel_Paging(t *testing.T) {
+	items := []steps.Item{}
+	for _, label := range []string{"e", "d", "c", "b", "a", "f", "g", "h"} {
+		items = append(items, steps.NewItem(label, label, "", []string{}))
+	}
+	var m tea.Model = InitialSelectInputModel(items, &Selection{}, "Select", false, program.NewProgram())
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 16})
+
+	size := m.(model).pageSize(0)
+	if size < 1 || size >= len(items) {
+		t.Fatalf("pageSize(0) = %d, want a page smaller than %d", size, len(items))
+	}
+	for i := 0; i < len(items)-1; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	got := m.(model)
+	if got.offset == 0 || got.cursor < got.offset || got.cursor >= got.offset+got.pageSize(got.offset) {
+		t.Errorf("cursor = %d, offset = %d, want the cursor on the page", got.cursor, got.offset)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	got = m.(model)
+	if len(got.visible) != 1 || got.choices[got.visible[0]].Value != "b" || got.offset != 0 {
+		t.Errorf("visible = %v, offset = %d, want only b", got.visible, got.offset)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	selection := &Selection{}
+	got = m.(model)
+	got.choice = selection
+	if _, cmd := got.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil || selection.Choice != "b" {
+		t.Errorf("Choice = %q, want b", selection.Choice)
+	}
+}
@@ -4,11 +4,13 @@ package selectInput
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/arthurbcp/kuma/v2/cmd/ui/textInput"
 	"github.com/arthurbcp/kuma/v2/cmd/ui/utils/program"
 	"github.com/arthurbcp/kuma/v2/cmd/ui/utils/steps"
 	"github.com/arthurbcp/kuma/v2/pkg/style"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -26,13 +28,19 @@ func (s *Selection) Update(value string) {
 //
 // It has the required methods that make it a bubbletea.Model
 type model struct {
-	cursor   int
-	choices  []steps.Item
-	selected map[int]struct{}
-	choice   *Selection
-	header   string
-	other    bool
-	program  *program.Program
+	cursor    int
+	choices   []steps.Item
+	visible   []int
+	selected  map[int]struct{}
+	choice    *Selection
+	header    string
+	other     bool
+	program   *program.Program
+	filter    textinput.Model
+	filtering bool
+	grouped   bool
+	height    int
+	offset    int
 }
 
 func (m model) Init() tea.Cmd {
@@ -40,15 +48,30 @@ func (m model) Init() tea.Cmd {
 }
 
 // InitialSelectInputModel initializes a multiInput step with
-// the given data
+// the given data. The choices are sorted by group and label.
 func InitialSelectInputModel(choices []steps.Item, selection *Selection, header string, other bool, program *program.Program) model {
+	sorted := make([]steps.Item, len(choices))
+	copy(sorted, choices)
+	sortItems(sorted)
+	grouped := false
+	for _, choice := range sorted {
+		if choice.Group != "" {
+			grouped = true
+		}
+	}
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "type to filter"
 	m := model{
-		choices:  choices,
+		choices:  sorted,
+		visible:  filterItems(sorted, ""),
 		selected: make(map[int]struct{}),
 		choice:   selection,
 		header:   style.TitleStyle.Render(header),
 		program:  program,
 		other:    other,
+		filter:   filter,
+		grouped:  grouped,
 	}
 	return m
 }
@@ -58,34 +81,51 @@ func InitialSelectInputModel(choices []steps.Item, selection *Selection, header
 // and confirm the selection.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
 	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.program.Exit = true
 			return m, tea.Quit
+		case "/":
+			m.filtering = true
+			return m, m.filter.Focus()
+		case "esc":
+			m.setFilter("")
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
 			}
 		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
+			if m.cursor < len(m.visible)-1 {
 				m.cursor++
 			}
+		case "pgup", "left", "h":
+			m.cursor = max(m.cursor-m.pageSize(m.offset), 0)
+		case "pgdown", "right", "l":
+			m.cursor = max(min(m.cursor+m.pageSize(m.offset), len(m.visible)-1), 0)
 		case "enter", " ":
+			if len(m.visible) == 0 {
+				break
+			}
+			index := m.visible[m.cursor]
 			if len(m.selected) == 1 {
 				m.selected = make(map[int]struct{})
 			}
-			_, ok := m.selected[m.cursor]
+			_, ok := m.selected[index]
 			if ok {
-				delete(m.selected, m.cursor)
+				delete(m.selected, index)
 			} else {
-				m.selected[m.cursor] = struct{}{}
+				m.selected[index] = struct{}{}
 			}
 		case "y":
 			if len(m.selected) == 1 {
 				for selectedKey := range m.selected {
 					m.choice.Update(m.choices[selectedKey].Value)
-					m.cursor = selectedKey
 				}
 				return m, tea.Quit
 			}
@@ -104,13 +144,136 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	}
+	m.scroll()
 	return m, nil
 }
 
+// updateFilter handles the keys typed while the filter is focused, narrowing
+// the options as the user types.
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.program.Exit = true
+		return m, tea.Quit
+	case "esc":
+		m.filtering = false
+		m.filter.Blur()
+		m.setFilter("")
+	case "enter":
+		m.filtering = false
+		m.filter.Blur()
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+	default:
+		var cmd tea.Cmd
+		m.filter, cmd = m.filter.Update(msg)
+		m.visible = filterItems(m.choices, m.filter.Value())
+		m.cursor, m.offset = 0, 0
+		m.scroll()
+		return m, cmd
+	}
+	m.scroll()
+	return m, nil
+}
+
+// setFilter replaces the filter and shows the options that match it
+func (m *model) setFilter(value string) {
+	m.filter.SetValue(value)
+	m.visible = filterItems(m.choices, value)
+	m.cursor, m.offset = 0, 0
+}
+
+// itemLines returns the number of lines the option at position i of the
+// visible options takes on the screen, its group heading included.
+func (m model) itemLines(i int, first bool) int {
+	item := m.choices[m.visible[i]]
+	lines := 2 + strings.Count(item.Description+item.Tags, "\n")
+	if m.grouped && (first || item.Group != m.choices[m.visible[i-1]].Group) {
+		lines++
+	}
+	return lines
+}
+
+// pageSize returns how many options fit on the screen starting at offset.
+// Every option fits when the terminal height is unknown.
+func (m model) pageSize(offset int) int {
+	if m.height == 0 {
+		return len(m.visible) - offset
+	}
+	available := m.height - strings.Count(m.top()+m.bottom(0), "\n") - 1
+	size, lines := 0, 0
+	for i := offset; i < len(m.visible); i++ {
+		lines += m.itemLines(i, i == offset)
+		if size > 0 && lines > available {
+			break
+		}
+		size++
+	}
+	return size
+}
+
+// scroll moves the page so the cursor is always visible
+func (m *model) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	for m.offset < m.cursor && m.cursor >= m.offset+m.pageSize(m.offset) {
+		m.offset++
+	}
+}
+
+// top draws the header and the filter
+func (m model) top() string {
+	s := m.header + "\n\n"
+	if m.filtering || m.filter.Value() != "" {
+		s += m.filter.View() + "\n\n"
+	}
+	return s
+}
+
+// bottom draws the position in the list, showing size options, and the help
+func (m model) bottom(size int) string {
+	s := ""
+	if len(m.visible) == 0 {
+		s += "No options match the filter.\n\n"
+	} else if size < len(m.visible) {
+		s += fmt.Sprintf("%d-%d of %d\n\n", m.offset+1, m.offset+size, len(m.visible))
+	} else {
+		s += "\n"
+	}
+	if m.filtering {
+		s += fmt.Sprintf("Press %s to apply the filter.\n", style.FocusedStyle.Render("enter"))
+		s += fmt.Sprintf("Press %s to clear the filter.\n", style.FocusedStyle.Render("esc"))
+		s += "\n"
+		return s
+	}
+	s += fmt.Sprintf("Press %s to confirm choice.\n", style.FocusedStyle.Render("y"))
+	s += fmt.Sprintf("Press %s to filter the options.\n", style.FocusedStyle.Render("/"))
+	if m.other {
+		s += fmt.Sprintf("Press %s to text another option.\n", style.FocusedStyle.Render("o"))
+	}
+	s += fmt.Sprintf("Press %s to quit.\n", style.FocusedStyle.Render("q"))
+	s += "\n"
+	return s
+}
+
 // View is called to draw the multiInput step
 func (m model) View() string {
-	s := m.header + "\n\n"
-	for i, choice := range m.choices {
+	s := m.top()
+	size := m.pageSize(m.offset)
+	for i := m.offset; i < m.offset+size; i++ {
+		index := m.visible[i]
+		choice := m.choices[index]
+		if m.grouped && (i == m.offset || choice.Group != m.choices[m.visible[i-1]].Group) {
+			s += style.TagsStyle.Render(choice.Group) + "\n"
+		}
+
 		cursor := " "
 		if m.cursor == i {
 			cursor = style.SelectedItemStyle.Render(">")
@@ -120,7 +283,7 @@ func (m model) View() string {
 		}
 
 		checked := " "
-		if _, ok := m.selected[i]; ok {
+		if _, ok := m.selected[index]; ok {
 			checked = style.SelectedItemStyle.Render("*")
 		}
 
@@ -130,12 +293,6 @@ func (m model) View() string {
 
 		s += fmt.Sprintf("%s [%s] %s%s%s\n\n", cursor, checked, label, description, tags)
 	}
-
-	s += fmt.Sprintf("Press %s to confirm choice.\n", style.FocusedStyle.Render("y"))
-	if m.other {
-		s += fmt.Sprintf("Press %s to text another option.\n", style.FocusedStyle.Render("o"))
-	}
-	s += fmt.Sprintf("Press %s to quit.\n", style.FocusedStyle.Render("q"))
-	s += "\n"
+	s += m.bottom(size)
 	return s
 }
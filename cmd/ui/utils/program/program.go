@@ -1,10 +1,11 @@
 package program
 
 import (
-	"fmt"
 	"log"
 	"os"
 
+	"github.com/arthurbcp/kuma/v2/cmd/shared"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -18,12 +19,13 @@ func NewProgram() *Program {
 	}
 }
 
+// ExitCLI stops kuma when the user quit the program, e.g. with Ctrl+C or esc,
+// giving the terminal back and exiting with the interrupted exit code.
 func (p *Program) ExitCLI(tprogram *tea.Program) {
 	if p.Exit {
-		fmt.Print("saindo")
 		if err := tprogram.ReleaseTerminal(); err != nil {
 			log.Fatal(err)
 		}
-		os.Exit(0)
+		os.Exit(shared.ExitCodeInterrupted)
 	}
 }
@@ -22,9 +22,14 @@ type Steps struct {
 // in a StepSchema.Options
 type Item struct {
 	Label, Value, Description, Tags string
+	// Group is the heading the item is listed under, e.g. its source file
+	Group string
+	// Keywords is the plain text searched when the options are filtered
+	Keywords string
 }
 
 func NewItem(label, value, description string, tags []string) Item {
+	keywords := strings.Join(append([]string{label, description}, tags...), " ")
 	if description != "" {
 		description = "\n\t\t" + description
 	}
@@ -37,5 +42,12 @@ func NewItem(label, value, description string, tags []string) Item {
 		Value:       value,
 		Description: description,
 		Tags:        tagsStr,
+		Keywords:    keywords,
 	}
 }
+
+// WithGroup returns a copy of the item listed under the given group
+func (i Item) WithGroup(group string) Item {
+	i.Group = group
+	return i
+}
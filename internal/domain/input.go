@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Input types supported by RunInput.
+const (
+	StringInput = "string"
+	IntInput    = "int"
+	BoolInput   = "bool"
+	ListInput   = "list"
+	MapInput    = "map"
+)
+
+// RunInput declares a parameter of a run, validated before its first step.
+type RunInput struct {
+	// Name is the .data variable that holds the input value.
+	Name string `json:"name" mapstructure:"-"`
+
+	// Type is one of string, int, bool, list or map. Defaults to string.
+	Type string `json:"type" mapstructure:"type"`
+
+	// Description explains the input to the user.
+	Description string `json:"description" mapstructure:"description"`
+
+	// Default is used when the input has no value.
+	Default interface{} `json:"default" mapstructure:"default"`
+
+	// Required makes the run fail when the input has no value nor default.
+	Required bool `json:"required" mapstructure:"required"`
+
+	// Enum lists the accepted values.
+	Enum []interface{} `json:"enum" mapstructure:"enum"`
+
+	// Pattern is a regular expression that string values must match.
+	Pattern string `json:"pattern" mapstructure:"pattern"`
+}
+
+// NewRunInputs parses the inputs block of a run, sorted by name.
+//
+// Parameters:
+//   - inputs: A map of input names to their declarations.
+//
+// Returns:
+//
+//	The parsed inputs and an error if a declaration is invalid.
+func NewRunInputs(inputs map[string]interface{}) ([]RunInput, error) {
+	runInputs := make([]RunInput, 0, len(inputs))
+	for name, spec := range inputs {
+		input := RunInput{}
+		if spec != nil {
+			if err := mapstructure.Decode(spec, &input); err != nil {
+				return nil, fmt.Errorf("invalid input %s: %s", name, err.Error())
+			}
+		}
+		input.Name = name
+		if input.Type == "" {
+			input.Type = StringInput
+		}
+		switch input.Type {
+		case StringInput, IntInput, BoolInput, ListInput, MapInput:
+		default:
+			return nil, fmt.Errorf("invalid input %s: unknown type %s", name, input.Type)
+		}
+		if input.Pattern != "" {
+			if _, err := regexp.Compile(input.Pattern); err != nil {
+				return nil, fmt.Errorf("invalid input %s: invalid pattern: %s", name, err.Error())
+			}
+		}
+		runInputs = append(runInputs, input)
+	}
+	sort.Slice(runInputs, func(i, j int) bool {
+		return runInputs[i].Name < runInputs[j].Name
+	})
+	return runInputs, nil
+}
+
+// Missing reports whether the input has no value in data and no default.
+func (i RunInput) Missing(data map[string]interface{}) bool {
+	value, ok := data[i.Name]
+	return (!ok || value == nil) && i.Default == nil
+}
+
+// Resolve validates the value of the input in data, applying its default and
+// coercing it to the input type.
+//
+// Parameters:
+//   - data: The .data variables of the run.
+//
+// Returns:
+//
+//	The coerced value, whether the input has a value, and an error if the
+//	value is invalid or a required input is missing.
+func (i RunInput) Resolve(data map[string]interface{}) (interface{}, bool, error) {
+	value, ok := data[i.Name]
+	if !ok || value == nil {
+		value = i.Default
+	}
+	if value == nil {
+		if i.Required {
+			return nil, false, fmt.Errorf("input %s is required", i.Name)
+		}
+		return nil, false, nil
+	}
+
+	value, err := coerce(i.Type, value)
+	if err != nil {
+		return nil, false, fmt.Errorf("input %s: %s", i.Name, err.Error())
+	}
+
+	if len(i.Enum) > 0 {
+		found := false
+		for _, allowed := range i.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false, fmt.Errorf("input %s: %v is not one of %v", i.Name, value, i.Enum)
+		}
+	}
+
+	if i.Pattern != "" {
+		if str, ok := value.(string); ok && !regexp.MustCompile(i.Pattern).MatchString(str) {
+			return nil, false, fmt.Errorf("input %s: %q does not match %s", i.Name, str, i.Pattern)
+		}
+	}
+	return value, true, nil
+}
+
+// ResolveInputs resolves every input into data, reporting all the invalid
+// inputs at once.
+func ResolveInputs(inputs []RunInput, data map[string]interface{}) error {
+	errs := []string{}
+	for _, input := range inputs {
+		value, ok, err := input.Resolve(data)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if ok {
+			data[input.Name] = value
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid inputs:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// coerce converts values given as strings, e.g. through --set, to the
+// input type.
+func coerce(inputType string, value interface{}) (interface{}, error) {
+	switch inputType {
+	case StringInput:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("expected a string, got %v", value)
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case IntInput:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("expected an int, got %q", v)
+			}
+			return n, nil
+		default:
+			return nil, fmt.Errorf("expected an int, got %v", value)
+		}
+	case BoolInput:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("expected a bool, got %q", v)
+			}
+			return b, nil
+		default:
+			return nil, fmt.Errorf("expected a bool, got %v", value)
+		}
+	case ListInput:
+		switch v := value.(type) {
+		case []interface{}:
+			return v, nil
+		case []string:
+			list := make([]interface{}, len(v))
+			for i, item := range v {
+				list[i] = item
+			}
+			return list, nil
+		case string:
+			list := []interface{}{}
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			return list, nil
+		default:
+			return nil, fmt.Errorf("expected a list, got %v", value)
+		}
+	case MapInput:
+		if v, ok := value.(map[string]interface{}); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected a map, got %v", value)
+	}
+	return nil, fmt.Errorf("unknown type %s", inputType)
+}
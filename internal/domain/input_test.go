@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRunInputs(t *testing.T) {
+	inputs, err := NewRunInputs(map[string]interface{}{
+		"replicas": map[string]interface{}{"type": "int", "default": 1},
+		"name":     map[string]interface{}{"required": true, "pattern": "^[a-z-]+$"},
+		"tags":     nil,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []RunInput{
+		{Name: "name", Type: StringInput, Required: true, Pattern: "^[a-z-]+$"},
+		{Name: "replicas", Type: IntInput, Default: 1},
+		{Name: "tags", Type: StringInput},
+	}, inputs)
+
+	_, err = NewRunInputs(map[string]interface{}{"port": map[string]interface{}{"type": "number"}})
+	assert.Error(t, err)
+
+	_, err = NewRunInputs(map[string]interface{}{"name": map[string]interface{}{"pattern": "("}})
+	assert.Error(t, err)
+}
+
+func TestRunInput_Resolve(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   RunInput
+		data    map[string]interface{}
+		want    interface{}
+		wantOk  bool
+		wantErr bool
+	}{
+		{
+			name:   "String value",
+			input:  RunInput{Name: "name", Type: StringInput},
+			data:   map[string]interface{}{"name": "users"},
+			want:   "users",
+			wantOk: true,
+		},
+		{
+			name:   "Int from string",
+			input:  RunInput{Name: "port", Type: IntInput},
+			data:   map[string]interface{}{"port": "8080"},
+			want:   8080,
+			wantOk: true,
+		},
+		{
+			name:    "Invalid int",
+			input:   RunInput{Name: "port", Type: IntInput},
+			data:    map[string]interface{}{"port": "http"},
+			wantErr: true,
+		},
+		{
+			name:   "Bool from string",
+			input:  RunInput{Name: "private", Type: BoolInput},
+			data:   map[string]interface{}{"private": "true"},
+			want:   true,
+			wantOk: true,
+		},
+		{
+			name:   "List from comma separated string",
+			input:  RunInput{Name: "tags", Type: ListInput},
+			data:   map[string]interface{}{"tags": "a, b"},
+			want:   []interface{}{"a", "b"},
+			wantOk: true,
+		},
+		{
+			name:    "Invalid map",
+			input:   RunInput{Name: "labels", Type: MapInput},
+			data:    map[string]interface{}{"labels": "a=b"},
+			wantErr: true,
+		},
+		{
+			name:   "Default value",
+			input:  RunInput{Name: "replicas", Type: IntInput, Default: 2},
+			data:   map[string]interface{}{},
+			want:   2,
+			wantOk: true,
+		},
+		{
+			name:    "Missing required value",
+			input:   RunInput{Name: "name", Type: StringInput, Required: true},
+			data:    map[string]interface{}{},
+			wantErr: true,
+		},
+		{
+			name:   "Missing optional value",
+			input:  RunInput{Name: "name", Type: StringInput},
+			data:   map[string]interface{}{},
+			wantOk: false,
+		},
+		{
+			name:    "Value outside enum",
+			input:   RunInput{Name: "runtime", Type: StringInput, Enum: []interface{}{"node", "bun"}},
+			data:    map[string]interface{}{"runtime": "deno"},
+			wantErr: true,
+		},
+		{
+			name:    "Value not matching pattern",
+			input:   RunInput{Name: "name", Type: StringInput, Pattern: "^[a-z-]+$"},
+			data:    map[string]interface{}{"name": "Users Service"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, err := tc.input.Resolve(tc.data)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestResolveInputs(t *testing.T) {
+	inputs := []RunInput{
+		{Name: "name", Type: StringInput, Required: true},
+		{Name: "port", Type: IntInput},
+		{Name: "replicas", Type: IntInput, Default: 1},
+	}
+
+	data := map[string]interface{}{"name": "users", "port": "8080"}
+	assert.NoError(t, ResolveInputs(inputs, data))
+	assert.Equal(t, map[string]interface{}{"name": "users", "port": 8080, "replicas": 1}, data)
+
+	err := ResolveInputs(inputs, map[string]interface{}{"port": "http"})
+	assert.ErrorContains(t, err, "input name is required")
+	assert.ErrorContains(t, err, "input port: expected an int")
+}
@@ -1,11 +1,19 @@
 package domain
 
+import "time"
+
 type Run struct {
 	Key         string        `json:"key"`
 	Description string        `json:"description"`
 	Steps       []interface{} `json:"steps"`
+	Finally     []interface{} `json:"finally"`
+	Inputs      []RunInput    `json:"inputs"`
 	File        string        `json:"file"`
 	Visible     bool          `json:"visible"`
+
+	// Timeout stops the run, including its nested runs, when it takes longer.
+	// Zero means no timeout.
+	Timeout time.Duration `json:"timeout"`
 }
 
 func NewRun(key string, description string, steps []interface{}, file string, visible bool) Run {
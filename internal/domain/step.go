@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys of the built-in step handlers.
+const (
+	CreateHandler = "create"
+	LoadHandler   = "load"
+	LogHandler    = "log"
+	RunHandler    = "run"
+	WhenHandler   = "when"
+	ModifyHandler = "modify"
+	CmdHandler    = "cmd"
+	FormHandler   = "form"
+	DefineHandler = "define"
+	EachHandler   = "each"
+	SwitchHandler = "switch"
+	HttpHandler   = "http"
+	AssertHandler = "assert"
+	FileHandler   = "file"
+	RenderHandler = "render"
+)
+
+// Keys of the step modifiers.
+const (
+	NameModifier            = "name"
+	IfModifier              = "if"
+	ContinueOnErrorModifier = "continue-on-error"
+	RetryModifier           = "retry"
+	OnErrorModifier         = "on-error"
+	RegisterModifier        = "register"
+)
+
+// StepModifiers are the step keys that change how a step is executed instead
+// of naming the handler that executes it.
+var StepModifiers = map[string]bool{
+	NameModifier:            true,
+	IfModifier:              true,
+	ContinueOnErrorModifier: true,
+	RetryModifier:           true,
+	OnErrorModifier:         true,
+	RegisterModifier:        true,
+}
+
+// nestedStepParams are the params of the built-in handlers that hold a list
+// of steps. The steps of the cases of a switch are validated apart. The
+// params of other handlers, plugins included, belong to them and are never
+// validated as steps.
+var nestedStepParams = map[string][]string{
+	WhenHandler:   {"then", "else"},
+	EachHandler:   {"steps"},
+	SwitchHandler: {"default"},
+}
+
+// ValidateRunFile checks that every step of every run in a run file, nested
+// steps included, has exactly one handler key besides its modifiers.
+//
+// Parameters:
+//   - file: The name of the run file, used in the error messages.
+//   - content: The YAML or JSON content of the run file.
+//
+// Returns:
+//
+//	An error listing every invalid step with its file and line.
+func ValidateRunFile(file string, content []byte) error {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(content, root); err != nil {
+		return fmt.Errorf("%s: %s", file, err.Error())
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	errs := []string{}
+	runs := root.Content[0]
+	for i := 0; i+1 < len(runs.Content); i += 2 {
+		run := runs.Content[i+1]
+		if run.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(run.Content); j += 2 {
+			switch run.Content[j].Value {
+			case "steps", "finally":
+				errs = append(errs, validateSteps(file, run.Content[j+1])...)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid steps:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+func validateSteps(file string, steps *yaml.Node) []string {
+	errs := []string{}
+	if steps.Kind != yaml.SequenceNode {
+		return append(errs, fmt.Sprintf("%s:%d: steps must be a list", file, steps.Line))
+	}
+	for _, step := range steps.Content {
+		errs = append(errs, validateStep(file, step)...)
+	}
+	return errs
+}
+
+func validateStep(file string, step *yaml.Node) []string {
+	errs := []string{}
+	if step.Kind != yaml.MappingNode {
+		return append(errs, fmt.Sprintf("%s:%d: step must be a map", file, step.Line))
+	}
+	handlers := []string{}
+	for i := 0; i+1 < len(step.Content); i += 2 {
+		key, value := step.Content[i], step.Content[i+1]
+		if !StepModifiers[key.Value] {
+			handlers = append(handlers, key.Value)
+			errs = append(errs, validateParams(file, key.Value, value)...)
+			continue
+		}
+		if key.Value == OnErrorModifier {
+			errs = append(errs, validateSteps(file, value)...)
+		}
+	}
+	switch len(handlers) {
+	case 0:
+		errs = append([]string{fmt.Sprintf("%s:%d: step has no handler", file, step.Line)}, errs...)
+	case 1:
+	default:
+		sort.Strings(handlers)
+		errs = append([]string{fmt.Sprintf("%s:%d: step has more than one handler: %s", file, step.Line, strings.Join(handlers, ", "))}, errs...)
+	}
+	return errs
+}
+
+// validateParams validates the steps nested in the params of a built-in
+// handler, e.g. the branches of a when or the cases of a switch. Params with
+// the same names that are not lists are left to the handler.
+func validateParams(file string, handler string, params *yaml.Node) []string {
+	errs := []string{}
+	if params.Kind != yaml.MappingNode {
+		return errs
+	}
+	for i := 0; i+1 < len(params.Content); i += 2 {
+		key, value := params.Content[i], params.Content[i+1]
+		if value.Kind != yaml.SequenceNode {
+			continue
+		}
+		switch {
+		case isNestedStepParam(handler, key.Value):
+			errs = append(errs, validateSteps(file, value)...)
+		case handler == SwitchHandler && key.Value == "cases":
+			for _, c := range value.Content {
+				errs = append(errs, validateCase(file, c)...)
+			}
+		}
+	}
+	return errs
+}
+
+// validateCase validates the steps of a case of a switch.
+func validateCase(file string, c *yaml.Node) []string {
+	if c.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(c.Content); i += 2 {
+		if c.Content[i].Value == "steps" && c.Content[i+1].Kind == yaml.SequenceNode {
+			return validateSteps(file, c.Content[i+1])
+		}
+	}
+	return nil
+}
+
+func isNestedStepParam(handler string, param string) bool {
+	for _, p := range nestedStepParams[handler] {
+		if p == param {
+			return true
+		}
+	}
+	return false
+}
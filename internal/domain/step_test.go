@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRunFile(t *testing.T) {
+	valid := `
+init:
+  steps:
+    - name: greet
+      if: "{{.data.greet}}"
+      log: hello
+    - when:
+        condition: "true"
+        then:
+          - log: then
+        else:
+          - cmd: echo else
+    - form:
+        fields:
+          - input:
+              out: name
+              default: users
+  finally:
+    - log: done
+`
+	assert.NoError(t, ValidateRunFile("init.yaml", []byte(valid)))
+
+	plugin := `
+init:
+  steps:
+    - deploy:
+        steps:
+          - staging
+          - production
+        default:
+          - name: only-a-name
+`
+	assert.NoError(t, ValidateRunFile("init.yaml", []byte(plugin)), "params of plugins are not steps")
+
+	invalid := `
+init:
+  steps:
+    - log: hello
+      cmd: echo hello
+    - name: nothing
+    - switch:
+        value: a
+        cases:
+          - value: a
+            steps:
+              - log: a
+                define:
+                  variable: a
+    - cmd: fail
+      on-error:
+        - continue-on-error: true
+`
+	err := ValidateRunFile("init.yaml", []byte(invalid))
+	assert.EqualError(t, err, "invalid steps:\n"+
+		"  init.yaml:4: step has more than one handler: cmd, log\n"+
+		"  init.yaml:6: step has no handler\n"+
+		"  init.yaml:12: step has more than one handler: define, log\n"+
+		"  init.yaml:17: step has no handler")
+}
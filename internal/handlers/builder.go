@@ -9,6 +9,7 @@ import (
 	"github.com/arthurbcp/kuma/v2/internal/domain"
 	"github.com/arthurbcp/kuma/v2/internal/functions"
 	"github.com/arthurbcp/kuma/v2/internal/helpers"
+	"github.com/arthurbcp/kuma/v2/pkg/filesystem"
 	"github.com/arthurbcp/kuma/v2/pkg/style"
 	"github.com/spf13/afero"
 )
@@ -115,23 +116,14 @@ func (h *BuilderHandler) createDirAndFilesRecursive(key string, node interface{}
 //
 //	An error if file creation or template application fails, otherwise nil.
 func (h *BuilderHandler) createFileAndApplyTemplate(currentPath string, fileName string, data map[string]interface{}) error {
-	filePath := filepath.Join(currentPath, fileName)
-
-	file, err := h.builder.Fs.CreateFile(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
 	t, err := h.getTemplate(data)
 	if err != nil {
 		return err
 	}
-	data = map[string]interface{}{
+	return RenderTemplateToFile(h.builder.Fs, filepath.Join(currentPath, fileName), t, map[string]interface{}{
 		"data":   data["data"],
 		"global": h.builder.Data.Global,
-	}
-	return t.Execute(file, data)
+	})
 }
 
 // getTemplate retrieves and parses the template files based on the provided data.
@@ -143,14 +135,35 @@ func (h *BuilderHandler) createFileAndApplyTemplate(currentPath string, fileName
 //
 //	A pointer to the parsed template.Template and an error if parsing fails.
 func (h *BuilderHandler) getTemplate(data map[string]interface{}) (*template.Template, error) {
+	return ParseTemplate(h.builder.Fs, h.builder.Config.TemplatesPath, data)
+}
+
+// ParseTemplate reads and parses a template and its includes, with every
+// function of functions.GetFuncMap. Absolute paths are not joined to
+// templatesPath.
+//
+// Parameters:
+//   - fs: The file system the templates are read from.
+//   - templatesPath: The directory the template and includes are relative to.
+//   - data: A map with the template name and, optionally, a list of includes.
+//
+// Returns:
+//
+//	A pointer to the parsed template.Template and an error if parsing fails.
+func ParseTemplate(fs filesystem.FileSystemInterface, templatesPath string, data map[string]interface{}) (*template.Template, error) {
 	templateName, ok := data["template"].(string)
 	if !ok || templateName == "" {
 		return nil, fmt.Errorf("template is required")
 	}
 
-	templateFile := filepath.Join(h.builder.Config.TemplatesPath, templateName)
+	resolve := func(name string) string {
+		if filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(templatesPath, name)
+	}
 
-	allTemplates := []string{templateFile}
+	allTemplates := []string{resolve(templateName)}
 
 	if includes, ok := data["includes"].([]interface{}); ok {
 		for _, include := range includes {
@@ -158,14 +171,14 @@ func (h *BuilderHandler) getTemplate(data map[string]interface{}) (*template.Tem
 			if !ok {
 				return nil, fmt.Errorf("invalid include type: %v", include)
 			}
-			allTemplates = append(allTemplates, filepath.Join(h.builder.Config.TemplatesPath, includeStr))
+			allTemplates = append(allTemplates, resolve(includeStr))
 		}
 	}
 
 	tmpl := template.New(templateName).Funcs(functions.GetFuncMap())
 
 	for _, tmplFile := range allTemplates {
-		content, err := afero.ReadFile(h.builder.Fs.GetAferoFs(), tmplFile)
+		content, err := afero.ReadFile(fs.GetAferoFs(), tmplFile)
 		if err != nil {
 			return nil, fmt.Errorf("error reading template file %s: %w", tmplFile, err)
 		}
@@ -178,3 +191,23 @@ func (h *BuilderHandler) getTemplate(data map[string]interface{}) (*template.Tem
 
 	return tmpl, nil
 }
+
+// RenderTemplateToFile creates or truncates a file and executes a template into it.
+//
+// Parameters:
+//   - fs: The file system the file is created in.
+//   - filePath: The path of the file.
+//   - tmpl: The parsed template.
+//   - data: The data the template is executed with.
+//
+// Returns:
+//
+//	An error if file creation or template execution fails, otherwise nil.
+func RenderTemplateToFile(fs filesystem.FileSystemInterface, filePath string, tmpl *template.Template, data interface{}) error {
+	file, err := fs.CreateFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return tmpl.Execute(file, data)
+}
@@ -157,3 +157,137 @@ func TestReplaceVars(t *testing.T) {
 		t.Errorf("ReplaceVars() = %v, want %v", result, expected)
 	}
 }
+
+func TestGetByPath(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "kuma",
+		"api": map[string]interface{}{
+			"paths": map[string]interface{}{"/users": "get"},
+		},
+	}
+	tests := []struct {
+		name   string
+		path   string
+		want   interface{}
+		wantOk bool
+	}{
+		{"Top level", "name", "kuma", true},
+		{"Nested", "api.paths", map[string]interface{}{"/users": "get"}, true},
+		{"Missing key", "api.tags", nil, false},
+		{"Through a non map value", "name.first", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetByPath(data, tt.path)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetByPath() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"cmd", "cmd", 0},
+		{"", "log", 3},
+		{"crate", "create", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"-"+tt.b, func(t *testing.T) {
+			if got := Levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("Levenshtein() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetByPath(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "kuma",
+		"api":  map[string]interface{}{"version": "v1"},
+	}
+	SetByPath(data, "api.name", "users")
+	SetByPath(data, "team.lead.name", "ana")
+	SetByPath(data, "name.first", "kuma")
+
+	want := map[string]interface{}{
+		"name": map[string]interface{}{"first": "kuma"},
+		"api":  map[string]interface{}{"version": "v1", "name": "users"},
+		"team": map[string]interface{}{
+			"lead": map[string]interface{}{"name": "ana"},
+		},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("SetByPath() = %v, want %v", data, want)
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "npm install", want: []string{"npm", "install"}},
+		{line: `git commit -m "initial commit"`, want: []string{"git", "commit", "-m", "initial commit"}},
+		{line: `cp 'my file.txt' dir\ name/`, want: []string{"cp", "my file.txt", "dir name/"}},
+		{line: `echo "say \"hi\" to $USER" ''`, want: []string{"echo", `say "hi" to $USER`, ""}},
+		{line: `  go   test  `, want: []string{"go", "test"}},
+		{line: `echo "a | b"`, want: []string{"echo", "a | b"}},
+		{line: `echo "unterminated`, wantErr: true},
+		{line: `echo 'unterminated`, wantErr: true},
+		{line: `cat file | grep kuma`, wantErr: true},
+		{line: `cat file|grep kuma`, wantErr: true},
+		{line: `echo x>out`, wantErr: true},
+		{line: `make build&&make test`, wantErr: true},
+		{line: `echo "a"|cat`, wantErr: true},
+		{line: `curl "http://host/?a=1&b=2" 'x>y' a\|b`, want: []string{"curl", "http://host/?a=1&b=2", "x>y", "a|b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := SplitShellWords(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SplitShellWords() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitShellWords() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitShellWords() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceVarsInValue(t *testing.T) {
+	value := map[string]interface{}{
+		"name":  "{{.Name}}",
+		"tags":  []interface{}{"kuma", "{{.Name}}-tag"},
+		"count": 2,
+	}
+	vars := map[string]string{"Name": "users"}
+	want := map[string]interface{}{
+		"name":  "users",
+		"tags":  []interface{}{"kuma", "users-tag"},
+		"count": 2,
+	}
+
+	result, err := ReplaceVarsInValue(value, vars, nil)
+	if err != nil {
+		t.Errorf("ReplaceVarsInValue() error = %v", err)
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("ReplaceVarsInValue() = %v, want %v", result, want)
+	}
+}
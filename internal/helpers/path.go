@@ -0,0 +1,49 @@
+package helpers
+
+import "strings"
+
+// GetByPath returns the value found following a dotted path such as
+// "api.paths" inside nested maps.
+func GetByPath(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = currentMap[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// SetByPath sets the value found following a dotted path such as
+// "api.name", creating the intermediate maps that do not exist yet.
+func SetByPath(data map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	current := data
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// CopyMap returns a deep copy of the nested maps of data. Other values are
+// shared with the original map.
+func CopyMap(data map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if valueMap, ok := value.(map[string]interface{}); ok {
+			value = CopyMap(valueMap)
+		}
+		copied[key] = value
+	}
+	return copied
+}
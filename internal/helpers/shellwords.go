@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// shellOperators are the words that only have a meaning when a command runs
+// through a shell.
+var shellOperators = map[string]bool{
+	"|": true, "||": true, "&": true, "&&": true, ";": true,
+	">": true, ">>": true, "<": true, "2>": true, "2>&1": true,
+}
+
+// shellOperatorRunes are the characters of the shell operators, which also
+// act as operators when they are glued to a word, e.g. a|cat or x>out.
+const shellOperatorRunes = "|&;<>"
+
+// SplitShellWords splits a command line into its arguments following the
+// quoting rules of a POSIX shell: words are separated by blanks, single
+// quotes keep their content as is, double quotes keep it except for the
+// escaped ", \, $ and ` characters, and a backslash escapes the next
+// character outside quotes. Variables, globs and operators are not expanded.
+//
+// Parameters:
+//   - line: The command line to split.
+//
+// Returns:
+//
+//	The arguments and an error if a quote is not closed or the line uses a
+//	shell operator, such as a pipe, that needs a shell to run.
+func SplitShellWords(line string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	quoted := false
+	// operator is the first operator found unquoted inside the current word
+	operator := ""
+	flush := func() error {
+		if !inWord {
+			return nil
+		}
+		if !quoted && shellOperators[word.String()] {
+			return fmt.Errorf("%s needs a shell, set shell: true to use it", word.String())
+		}
+		if operator != "" {
+			return fmt.Errorf("%s needs a shell, set shell: true to use it or quote it", operator)
+		}
+		words = append(words, word.String())
+		word.Reset()
+		inWord, quoted = false, false
+		return nil
+	}
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case r == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+		case r == '\'':
+			inWord, quoted = true, true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %s", line)
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			i = end
+		case r == '"':
+			inWord, quoted = true, true
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '"' {
+					closed = true
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+				word.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote in %s", line)
+			}
+		default:
+			if operator == "" && strings.ContainsRune(shellOperatorRunes, r) {
+				end := i
+				for end < len(runes) && strings.ContainsRune(shellOperatorRunes, runes[end]) {
+					end++
+				}
+				operator = string(runes[i:end])
+			}
+			inWord = true
+			word.WriteRune(r)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
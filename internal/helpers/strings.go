@@ -0,0 +1,25 @@
+package helpers
+
+// Levenshtein returns the number of single character insertions, deletions
+// and substitutions needed to turn a into b.
+func Levenshtein(a, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
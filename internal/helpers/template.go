@@ -19,3 +19,33 @@ func ReplaceVars(text string, vars interface{}, funcs template.FuncMap) (string,
 	}
 	return buf.String(), nil
 }
+
+// ReplaceVarsInValue applies ReplaceVars to every string of a value, walking
+// through nested maps and lists. Other values are returned as they are.
+func ReplaceVarsInValue(value interface{}, vars interface{}, funcs template.FuncMap) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return ReplaceVars(v, vars, funcs)
+	case map[string]interface{}:
+		replaced := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := ReplaceVarsInValue(item, vars, funcs)
+			if err != nil {
+				return nil, err
+			}
+			replaced[key] = r
+		}
+		return replaced, nil
+	case []interface{}:
+		replaced := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := ReplaceVarsInValue(item, vars, funcs)
+			if err != nil {
+				return nil, err
+			}
+			replaced[i] = r
+		}
+		return replaced, nil
+	}
+	return value, nil
+}
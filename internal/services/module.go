@@ -116,7 +116,7 @@ func (s *ModuleService) GetModuleName(repo string) string {
 
 func (s *ModuleService) GetRun(module *domain.Module, runKey string, modulePath string) (*domain.Run, error) {
 	moduleRun := module.Runs[runKey]
-	runs, err := helpers.UnmarshalFile(modulePath+"/"+moduleRun.File, s.fs)
+	runs, err := readRunFile(modulePath+"/"+moduleRun.File, s.fs)
 	if err != nil {
 		return nil, err
 	}
@@ -124,24 +124,13 @@ func (s *ModuleService) GetRun(module *domain.Module, runKey string, modulePath
 	if !ok {
 		return nil, fmt.Errorf("run not found: %s", runKey)
 	}
-	description, ok := runContent.(map[string]interface{})["description"].(string)
+	runMap, ok := runContent.(map[string]interface{})
 	if !ok {
-		description = ""
+		return nil, fmt.Errorf("invalid run: %s", runKey)
 	}
-	steps, ok := runContent.(map[string]interface{})["steps"].([]interface{})
-	if !ok {
-		steps = []interface{}{}
+	run, err := parseRun(runKey, moduleRun.File, runMap)
+	if err != nil {
+		return nil, err
 	}
-	visible, ok := runContent.(map[string]interface{})["visible"].(bool)
-	if !ok {
-		visible = true
-	}
-	run := domain.NewRun(
-		runKey,
-		description,
-		steps,
-		moduleRun.File,
-		visible,
-	)
 	return &run, nil
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/arthurbcp/kuma/v2/internal/domain"
 	"github.com/arthurbcp/kuma/v2/internal/helpers"
@@ -31,7 +32,7 @@ func (s *RunService) GetAll(onlyVisible bool) (map[string]domain.Run, error) {
 	}
 	runs := make(map[string]domain.Run)
 	for _, fileName := range files {
-		data, err := helpers.UnmarshalFile(s.path+"/"+fileName, s.fs)
+		data, err := readRunFile(s.path+"/"+fileName, s.fs)
 		if err != nil {
 			return nil, err
 		}
@@ -39,28 +40,18 @@ func (s *RunService) GetAll(onlyVisible bool) (map[string]domain.Run, error) {
 			if _, ok := runs[key]; ok {
 				return nil, fmt.Errorf("conflict between runs found for the run %s\n rename one of them and try again", key)
 			}
-			steps, ok := run.(map[string]interface{})["steps"].([]interface{})
+			runContent, ok := run.(map[string]interface{})
 			if !ok {
-				steps = []interface{}{}
+				return nil, fmt.Errorf("invalid run %s in %s", key, fileName)
 			}
-			visible, ok := run.(map[string]interface{})["visible"].(bool)
-			if !ok {
-				visible = true
-			}
-			description, ok := run.(map[string]interface{})["description"].(string)
-			if !ok {
-				description = ""
+			parsedRun, err := parseRun(key, fileName, runContent)
+			if err != nil {
+				return nil, err
 			}
-			if onlyVisible && !visible {
+			if onlyVisible && !parsedRun.Visible {
 				continue
 			}
-			runs[key] = domain.NewRun(
-				key,
-				description,
-				steps,
-				fileName,
-				visible,
-			)
+			runs[key] = parsedRun
 		}
 	}
 
@@ -78,3 +69,63 @@ func (s *RunService) Get(name string) (*domain.Run, error) {
 	}
 	return &run, nil
 }
+
+// readRunFile reads a run file, rejecting it when one of its steps is invalid.
+func readRunFile(file string, fs filesystem.FileSystemInterface) (map[string]interface{}, error) {
+	content, err := fs.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateRunFile(file, []byte(content)); err != nil {
+		return nil, err
+	}
+	return helpers.UnmarshalByExt(file, []byte(content))
+}
+
+// parseRun builds a domain.Run from the content of a run file entry, applying
+// the defaults for every omitted property.
+func parseRun(key string, file string, content map[string]interface{}) (domain.Run, error) {
+	description, ok := content["description"].(string)
+	if !ok {
+		description = ""
+	}
+	steps, ok := content["steps"].([]interface{})
+	if !ok {
+		steps = []interface{}{}
+	}
+	visible, ok := content["visible"].(bool)
+	if !ok {
+		visible = true
+	}
+	run := domain.NewRun(
+		key,
+		description,
+		steps,
+		file,
+		visible,
+	)
+	if finally, ok := content["finally"].([]interface{}); ok {
+		run.Finally = finally
+	}
+	switch timeout := content["timeout"].(type) {
+	case int:
+		run.Timeout = time.Duration(timeout) * time.Second
+	case string:
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return run, fmt.Errorf("run %s: invalid timeout %s", key, timeout)
+		}
+		run.Timeout = duration
+	case nil:
+	default:
+		return run, fmt.Errorf("run %s: invalid timeout %v, use seconds or a duration", key, timeout)
+	}
+	if inputs, ok := content["inputs"].(map[string]interface{}); ok {
+		runInputs, err := domain.NewRunInputs(inputs)
+		if err != nil {
+			return run, fmt.Errorf("run %s: %s", key, err.Error())
+		}
+		run.Inputs = runInputs
+	}
+	return run, nil
+}
@@ -0,0 +1,245 @@
+package filesystem
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// NewDryRunFileSystem creates a FileSystem that reads from base but keeps every
+// write in an in-memory layer, so nothing is written to or staged on base.
+func NewDryRunFileSystem(base afero.Fs) *FileSystem {
+	layer := afero.NewMemMapFs()
+	overlay := &dryRunFs{
+		Fs:      afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(base), layer),
+		base:    base,
+		layer:   layer,
+		deleted: map[string]bool{},
+	}
+	return &FileSystem{
+		Fs:      overlay,
+		base:    base,
+		layer:   layer,
+		overlay: overlay,
+	}
+}
+
+// IsDryRun reports whether the file system was created by NewDryRunFileSystem.
+func (s *FileSystem) IsDryRun() bool {
+	return s.layer != nil
+}
+
+// Deleted lists, sorted, the paths of base deleted from a dry-run file system
+// and not created again. A deleted directory is listed without its files.
+func (s *FileSystem) Deleted() []string {
+	deleted := []string{}
+	if !s.IsDryRun() {
+		return deleted
+	}
+	for path := range s.overlay.deleted {
+		if !s.overlay.isDeleted(filepath.Dir(path)) {
+			deleted = append(deleted, path)
+		}
+	}
+	sort.Strings(deleted)
+	return deleted
+}
+
+// Changes lists the files written to a dry-run file system, split between the
+// files that do not exist on the base file system and the ones whose content
+// differs from it. Both slices are sorted by path.
+func (s *FileSystem) Changes() (created []string, modified []string, err error) {
+	if !s.IsDryRun() {
+		return nil, nil, nil
+	}
+	err = afero.Walk(s.layer, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		exists, err := afero.Exists(s.base, path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			created = append(created, path)
+			return nil
+		}
+		original, err := afero.ReadFile(s.base, path)
+		if err != nil {
+			return err
+		}
+		content, err := afero.ReadFile(s.layer, path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(original, content) {
+			modified = append(modified, path)
+		}
+		return nil
+	})
+	return created, modified, err
+}
+
+// dryRunFs is the overlay of a dry-run file system. The copy-on-write overlay
+// can only remove the files of its layer, so the files of base that are
+// deleted are hidden instead, until they are created again.
+type dryRunFs struct {
+	afero.Fs
+
+	base  afero.Fs
+	layer afero.Fs
+
+	// deleted holds every path of base hidden by a deletion.
+	deleted map[string]bool
+}
+
+func (d *dryRunFs) isDeleted(name string) bool {
+	return d.deleted[filepath.Clean(name)]
+}
+
+// restore shows a deleted path and its parent directories again, without the
+// other deleted files of those directories.
+func (d *dryRunFs) restore(name string) {
+	for path := filepath.Clean(name); ; path = filepath.Dir(path) {
+		delete(d.deleted, path)
+		if path == filepath.Dir(path) {
+			return
+		}
+	}
+}
+
+func notExist(op string, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+}
+
+func (d *dryRunFs) Stat(name string) (os.FileInfo, error) {
+	if d.isDeleted(name) {
+		return nil, notExist("stat", name)
+	}
+	return d.Fs.Stat(name)
+}
+
+func (d *dryRunFs) Open(name string) (afero.File, error) {
+	if d.isDeleted(name) {
+		return nil, notExist("open", name)
+	}
+	file, err := d.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &dryRunFile{File: file, fs: d}, nil
+}
+
+func (d *dryRunFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&os.O_CREATE != 0 {
+		d.restore(filepath.Dir(name))
+	}
+	if !d.isDeleted(name) {
+		file, err := d.Fs.OpenFile(name, flag, perm)
+		if err != nil {
+			return nil, err
+		}
+		return &dryRunFile{File: file, fs: d}, nil
+	}
+	if flag&os.O_CREATE == 0 {
+		return nil, notExist("open", name)
+	}
+	// a deleted file is created empty in the layer, not copied from base
+	d.restore(name)
+	if err := d.layer.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return nil, err
+	}
+	return d.layer.OpenFile(name, flag, perm)
+}
+
+func (d *dryRunFs) Create(name string) (afero.File, error) {
+	return d.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (d *dryRunFs) Mkdir(name string, perm os.FileMode) error {
+	if d.isDeleted(name) {
+		d.restore(name)
+		return d.layer.MkdirAll(name, perm)
+	}
+	return d.Fs.Mkdir(name, perm)
+}
+
+func (d *dryRunFs) MkdirAll(name string, perm os.FileMode) error {
+	d.restore(name)
+	return d.Fs.MkdirAll(name, perm)
+}
+
+func (d *dryRunFs) Remove(name string) error {
+	return d.RemoveAll(name)
+}
+
+// RemoveAll removes the path from the layer and hides every path of base
+// under it.
+func (d *dryRunFs) RemoveAll(name string) error {
+	if d.isDeleted(name) {
+		return nil
+	}
+	if err := d.layer.RemoveAll(name); err != nil {
+		return err
+	}
+	exists, err := afero.Exists(d.base, name)
+	if err != nil || !exists {
+		return err
+	}
+	return afero.Walk(d.base, name, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		d.deleted[filepath.Clean(path)] = true
+		return nil
+	})
+}
+
+func (d *dryRunFs) Rename(oldname, newname string) error {
+	if d.isDeleted(oldname) {
+		return notExist("rename", oldname)
+	}
+	d.restore(newname)
+	return d.Fs.Rename(oldname, newname)
+}
+
+func (d *dryRunFs) Chmod(name string, mode os.FileMode) error {
+	if d.isDeleted(name) {
+		return notExist("chmod", name)
+	}
+	return d.Fs.Chmod(name, mode)
+}
+
+// dryRunFile leaves the deleted paths out of the directory listings.
+type dryRunFile struct {
+	afero.File
+	fs *dryRunFs
+}
+
+func (f *dryRunFile) Readdir(count int) ([]os.FileInfo, error) {
+	infos, err := f.File.Readdir(count)
+	visible := make([]os.FileInfo, 0, len(infos))
+	for _, info := range infos {
+		if !f.fs.isDeleted(filepath.Join(f.Name(), info.Name())) {
+			visible = append(visible, info)
+		}
+	}
+	return visible, err
+}
+
+func (f *dryRunFile) Readdirnames(count int) ([]string, error) {
+	names, err := f.File.Readdirnames(count)
+	visible := make([]string, 0, len(names))
+	for _, name := range names {
+		if !f.fs.isDeleted(filepath.Join(f.Name(), name)) {
+			visible = append(visible, name)
+		}
+	}
+	return visible, err
+}
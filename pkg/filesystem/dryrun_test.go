@@ -0,0 +1,121 @@
+package filesystem
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestDryRunFileSystem(t *testing.T) {
+	base := afero.NewMemMapFs()
+	afero.WriteFile(base, "unchanged.txt", []byte("same"), 0644)
+	afero.WriteFile(base, "changed.txt", []byte("before"), 0644)
+
+	fs := NewDryRunFileSystem(base)
+	if !fs.IsDryRun() {
+		t.Fatalf("IsDryRun() = false, want true")
+	}
+
+	if err := fs.WriteFile("unchanged.txt", "same"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := fs.WriteFile("changed.txt", "after"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := fs.CreateDirectoryIfNotExists("new/dir"); err != nil {
+		t.Fatalf("CreateDirectoryIfNotExists() error = %v", err)
+	}
+	file, err := fs.CreateFile("new/dir/file.txt")
+	if err != nil {
+		t.Fatalf("CreateFile() error = %v", err)
+	}
+	file.Close()
+
+	content, err := fs.ReadFile("changed.txt")
+	if err != nil || content != "after" {
+		t.Errorf("ReadFile() = %q, %v, want %q", content, err, "after")
+	}
+	original, _ := afero.ReadFile(base, "changed.txt")
+	if string(original) != "before" {
+		t.Errorf("base file was modified: %q", original)
+	}
+	if exists, _ := afero.Exists(base, "new/dir/file.txt"); exists {
+		t.Errorf("base file system should not contain new/dir/file.txt")
+	}
+
+	created, modified, err := fs.Changes()
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if want := []string{"new/dir/file.txt"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("Changes() created = %v, want %v", created, want)
+	}
+	if want := []string{"changed.txt"}; !reflect.DeepEqual(modified, want) {
+		t.Errorf("Changes() modified = %v, want %v", modified, want)
+	}
+}
+
+func TestDryRunFileSystem_Delete(t *testing.T) {
+	base := afero.NewMemMapFs()
+	afero.WriteFile(base, "static/logo.png", []byte("logo"), 0644)
+	afero.WriteFile(base, "static/icons/app.svg", []byte("icon"), 0644)
+	afero.WriteFile(base, "README.tmpl.md", []byte("readme"), 0644)
+	afero.WriteFile(base, "old.txt", []byte("old"), 0644)
+	fs := NewDryRunFileSystem(base)
+
+	if err := fs.DeleteFile("static/logo.png"); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if exists, _ := afero.Exists(fs.Fs, "static/logo.png"); exists {
+		t.Errorf("static/logo.png exists after the delete")
+	}
+	if matches, _ := fs.Glob("static/*"); !reflect.DeepEqual(matches, []string{"static/icons"}) {
+		t.Errorf("Glob() = %v, want [static/icons]", matches)
+	}
+	if err := fs.DeleteFile("static/logo.png"); err == nil {
+		t.Errorf("DeleteFile() of a deleted file error = nil, want not found")
+	}
+
+	if err := fs.DeleteFile("static"); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if exists, _ := afero.Exists(fs.Fs, "static/icons/app.svg"); exists {
+		t.Errorf("static/icons/app.svg exists after deleting static")
+	}
+	if err := fs.WriteFile("static/new.txt", "new"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if matches, _ := fs.Glob("static/*"); !reflect.DeepEqual(matches, []string{"static/new.txt"}) {
+		t.Errorf("Glob() = %v, want only the new file", matches)
+	}
+
+	if err := fs.MoveFile("README.tmpl.md", "docs/README.md"); err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	if exists, _ := afero.Exists(fs.Fs, "README.tmpl.md"); exists {
+		t.Errorf("README.tmpl.md exists after the move")
+	}
+	if content, _ := fs.ReadFile("docs/README.md"); content != "readme" {
+		t.Errorf("docs/README.md = %q, want %q", content, "readme")
+	}
+
+	if err := fs.DeleteFile("old.txt"); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if err := fs.WriteFile("old.txt", "new"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if content, _ := fs.ReadFile("old.txt"); content != "new" {
+		t.Errorf("old.txt = %q, want the new content", content)
+	}
+
+	if want := []string{"README.tmpl.md", "static/icons", "static/logo.png"}; !reflect.DeepEqual(fs.Deleted(), want) {
+		t.Errorf("Deleted() = %v, want %v", fs.Deleted(), want)
+	}
+	for _, path := range []string{"static/logo.png", "static/icons/app.svg", "README.tmpl.md", "old.txt"} {
+		if exists, _ := afero.Exists(base, path); !exists {
+			t.Errorf("%s was removed from base", path)
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package filesystem
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os" // Import the os package
 	"os/exec"
+	"path/filepath"
 
 	"github.com/arthurbcp/kuma/v2/pkg/style"
 	"github.com/spf13/afero"
@@ -13,6 +15,12 @@ import (
 
 type FileSystem struct {
 	Fs afero.Fs
+
+	// base and layer are only set for dry-run file systems, where Fs is an
+	// overlay that keeps every write in layer and never touches base.
+	base    afero.Fs
+	layer   afero.Fs
+	overlay *dryRunFs
 }
 
 func NewFileSystem(fs afero.Fs) *FileSystem {
@@ -67,7 +75,12 @@ func (s *FileSystem) CreateFileIfNotExists(filename string) (afero.File, error)
 	return nil, nil
 }
 
+// AddFile stages a file with git. Files of in-memory and dry-run file systems
+// are never staged.
 func (s *FileSystem) AddFile(filename string) error {
+	if _, ok := s.Fs.(*afero.OsFs); !ok {
+		return nil
+	}
 	// Execute the git add command
 	execCmd := exec.Command("git", "add", filename)
 	// Set the command's standard output to the console
@@ -115,6 +128,81 @@ func (s *FileSystem) WriteFile(filename string, content string) error {
 	return nil
 }
 
+// Glob returns the sorted names of the files and directories matching
+// pattern, using the syntax of filepath.Match.
+func (s *FileSystem) Glob(pattern string) ([]string, error) {
+	return afero.Glob(s.Fs, pattern)
+}
+
+// CopyFile copies a file, or a directory with everything in it, creating the
+// parent directories of the destination. An existing destination file is
+// overwritten.
+func (s *FileSystem) CopyFile(src string, dst string) error {
+	return afero.Walk(s.Fs, src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return s.Fs.MkdirAll(target, os.ModePerm)
+		}
+		content, err := afero.ReadFile(s.Fs, path)
+		if err != nil {
+			return err
+		}
+		if err := s.Fs.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		exists, err := afero.Exists(s.Fs, target)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := s.AddFile(target); err != nil {
+				return fmt.Errorf("error adding file to git: %w", err)
+			}
+		}
+		if err := afero.WriteFile(s.Fs, target, content, info.Mode().Perm()); err != nil {
+			return err
+		}
+		return s.Fs.Chmod(target, info.Mode().Perm())
+	})
+}
+
+// MoveFile moves a file or a directory, creating the parent directories of
+// the destination.
+func (s *FileSystem) MoveFile(src string, dst string) error {
+	if _, ok := s.Fs.(*afero.OsFs); !ok {
+		// overlays and in-memory file systems can not rename every path
+		if err := s.CopyFile(src, dst); err != nil {
+			return err
+		}
+		return s.DeleteFile(src)
+	}
+	if err := s.Fs.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	return s.Fs.Rename(src, dst)
+}
+
+// DeleteFile removes a file or a directory with everything in it. Dry-run
+// file systems hide the deleted files of base instead.
+func (s *FileSystem) DeleteFile(path string) error {
+	if _, err := s.Fs.Stat(path); err != nil {
+		return err
+	}
+	return s.Fs.RemoveAll(path)
+}
+
+// Chmod changes the permissions of a file or directory.
+func (s *FileSystem) Chmod(path string, mode os.FileMode) error {
+	return s.Fs.Chmod(path, mode)
+}
+
 // ReadDir reads the directory named by path and returns a slice of file names.
 func (s *FileSystem) ReadDir(path string) ([]string, error) {
 	entries, err := afero.ReadDir(s.Fs, path)
@@ -134,9 +222,15 @@ func (s *FileSystem) GetAferoFs() afero.Fs {
 	return s.Fs
 }
 
-func (s *FileSystem) ReadFileFromURL(url string) (string, error) {
+// ReadFileFromURL downloads a file, stopping when ctx is canceled.
+func (s *FileSystem) ReadFileFromURL(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
 	// Send the HTTP GET request
-	resp, err := http.Get(url)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -1,6 +1,11 @@
 package filesystem
 
-import "github.com/spf13/afero"
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/afero"
+)
 
 //go:generate mockgen -source=filesystem_interface.go  -destination=./mocks/filesystem.go -package=filesystem_mocks
 type FileSystemInterface interface {
@@ -11,5 +16,10 @@ type FileSystemInterface interface {
 	CreateFile(filename string) (afero.File, error)
 	WriteFile(filename string, content string) error
 	ReadDir(path string) ([]string, error)
-	ReadFileFromURL(url string) (string, error)
+	ReadFileFromURL(ctx context.Context, url string) (string, error)
+	Glob(pattern string) ([]string, error)
+	CopyFile(src string, dst string) error
+	MoveFile(src string, dst string) error
+	DeleteFile(path string) error
+	Chmod(path string, mode os.FileMode) error
 }
@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFileSystem_FileOperations(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, "static/logo.png", []byte("logo"), 0644)
+	afero.WriteFile(memFs, "static/icons/app.svg", []byte("icon"), 0644)
+	afero.WriteFile(memFs, "scripts/setup.sh", []byte("#!/bin/sh"), 0644)
+	afero.WriteFile(memFs, "README.tmpl.md", []byte("readme"), 0644)
+	fs := NewFileSystem(memFs)
+
+	matches, err := fs.Glob("static/*")
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if want := []string{"static/icons", "static/logo.png"}; !reflect.DeepEqual(matches, want) {
+		t.Errorf("Glob() = %v, want %v", matches, want)
+	}
+
+	if err := fs.CopyFile("static", "public/assets"); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	for _, path := range []string{"public/assets/logo.png", "public/assets/icons/app.svg", "static/logo.png"} {
+		if exists, _ := afero.Exists(memFs, path); !exists {
+			t.Errorf("%s should exist after the copy", path)
+		}
+	}
+
+	if err := fs.MoveFile("README.tmpl.md", "docs/README.md"); err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	if content, _ := afero.ReadFile(memFs, "docs/README.md"); string(content) != "readme" {
+		t.Errorf("docs/README.md = %q, want %q", content, "readme")
+	}
+	if exists, _ := afero.Exists(memFs, "README.tmpl.md"); exists {
+		t.Errorf("README.tmpl.md should not exist after the move")
+	}
+
+	if err := fs.DeleteFile("static"); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if exists, _ := afero.Exists(memFs, "static/icons/app.svg"); exists {
+		t.Errorf("static should not exist after the deletion")
+	}
+	if err := fs.DeleteFile("missing.txt"); err == nil {
+		t.Errorf("DeleteFile() of a missing file should fail")
+	}
+
+	if err := fs.Chmod("scripts/setup.sh", 0755); err != nil {
+		t.Fatalf("Chmod() error = %v", err)
+	}
+	if info, _ := memFs.Stat("scripts/setup.sh"); info.Mode().Perm() != 0755 {
+		t.Errorf("scripts/setup.sh mode = %o, want 755", info.Mode().Perm())
+	}
+}
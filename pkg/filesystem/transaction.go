@@ -0,0 +1,281 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// FileSnapshot holds the content of a file before it was first changed.
+type FileSnapshot struct {
+	Path    string      `yaml:"path"`
+	Existed bool        `yaml:"existed"`
+	Content string      `yaml:"content,omitempty"`
+	Mode    os.FileMode `yaml:"mode,omitempty"`
+}
+
+// Snapshot holds everything needed to revert the changes of a transaction.
+type Snapshot struct {
+	// Files are the original contents of the changed files.
+	Files []FileSnapshot `yaml:"files"`
+
+	// Dirs are the directories created by the transaction.
+	Dirs []string `yaml:"dirs"`
+}
+
+// Transaction wraps a file system and snapshots every file before it is
+// changed for the first time, so that all the changes can be rolled back.
+type Transaction struct {
+	FileSystemInterface
+
+	files map[string]FileSnapshot
+	order []string
+	dirs  []string
+}
+
+// NewTransaction starts a transaction over fs.
+func NewTransaction(fs FileSystemInterface) *Transaction {
+	return &Transaction{
+		FileSystemInterface: fs,
+		files:               map[string]FileSnapshot{},
+	}
+}
+
+// CreateDirectoryIfNotExists records the directories that do not exist yet
+// before creating them.
+func (t *Transaction) CreateDirectoryIfNotExists(path string) error {
+	missing, err := t.missingDirs(path)
+	if err != nil {
+		return err
+	}
+	if err := t.FileSystemInterface.CreateDirectoryIfNotExists(path); err != nil {
+		return err
+	}
+	t.dirs = append(t.dirs, missing...)
+	return nil
+}
+
+// CreateFileIfNotExists snapshots the file before creating it.
+func (t *Transaction) CreateFileIfNotExists(filename string) (afero.File, error) {
+	if err := t.snapshot(filename); err != nil {
+		return nil, err
+	}
+	return t.FileSystemInterface.CreateFileIfNotExists(filename)
+}
+
+// CreateFile snapshots the file before creating or truncating it.
+func (t *Transaction) CreateFile(filename string) (afero.File, error) {
+	if err := t.snapshot(filename); err != nil {
+		return nil, err
+	}
+	return t.FileSystemInterface.CreateFile(filename)
+}
+
+// WriteFile snapshots the file before writing it.
+func (t *Transaction) WriteFile(filename string, content string) error {
+	if err := t.snapshot(filename); err != nil {
+		return err
+	}
+	return t.FileSystemInterface.WriteFile(filename, content)
+}
+
+// CopyFile snapshots every destination file before copying.
+func (t *Transaction) CopyFile(src string, dst string) error {
+	missing, err := t.snapshotTargets(src, dst)
+	if err != nil {
+		return err
+	}
+	if err := t.FileSystemInterface.CopyFile(src, dst); err != nil {
+		return err
+	}
+	t.dirs = append(t.dirs, missing...)
+	return nil
+}
+
+// MoveFile snapshots the moved files and every destination file before
+// moving.
+func (t *Transaction) MoveFile(src string, dst string) error {
+	if err := t.snapshotTree(src); err != nil {
+		return err
+	}
+	missing, err := t.snapshotTargets(src, dst)
+	if err != nil {
+		return err
+	}
+	if err := t.FileSystemInterface.MoveFile(src, dst); err != nil {
+		return err
+	}
+	t.dirs = append(t.dirs, missing...)
+	return nil
+}
+
+// DeleteFile snapshots every deleted file before deleting.
+func (t *Transaction) DeleteFile(path string) error {
+	if err := t.snapshotTree(path); err != nil {
+		return err
+	}
+	return t.FileSystemInterface.DeleteFile(path)
+}
+
+// Chmod snapshots the file, including its permissions, before changing them.
+func (t *Transaction) Chmod(path string, mode os.FileMode) error {
+	if err := t.snapshotTree(path); err != nil {
+		return err
+	}
+	return t.FileSystemInterface.Chmod(path, mode)
+}
+
+// Snapshot returns the original state of everything the transaction changed.
+func (t *Transaction) Snapshot() Snapshot {
+	snapshot := Snapshot{Dirs: append([]string{}, t.dirs...)}
+	for _, path := range t.order {
+		snapshot.Files = append(snapshot.Files, t.files[path])
+	}
+	return snapshot
+}
+
+// Rollback reverts every change made through the transaction.
+func (t *Transaction) Rollback() error {
+	return t.Snapshot().Restore(t.GetAferoFs())
+}
+
+func (t *Transaction) snapshot(filename string) error {
+	path := filepath.Clean(filename)
+	if _, ok := t.files[path]; ok {
+		return nil
+	}
+	snapshot := FileSnapshot{Path: path}
+	content, err := afero.ReadFile(t.GetAferoFs(), path)
+	if err == nil {
+		snapshot.Existed = true
+		snapshot.Content = string(content)
+		info, err := t.GetAferoFs().Stat(path)
+		if err != nil {
+			return err
+		}
+		snapshot.Mode = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	t.files[path] = snapshot
+	t.order = append(t.order, path)
+	return nil
+}
+
+// snapshotTree snapshots a file, or every file inside a directory.
+func (t *Transaction) snapshotTree(path string) error {
+	return afero.Walk(t.GetAferoFs(), path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return t.snapshot(file)
+	})
+}
+
+// snapshotTargets snapshots the files that copying src to dst would write and
+// returns the directories that copying would create.
+func (t *Transaction) snapshotTargets(src string, dst string) ([]string, error) {
+	missing := []string{}
+	seen := map[string]bool{}
+	err := afero.Walk(t.GetAferoFs(), src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		dir := target
+		if !info.IsDir() {
+			if err := t.snapshot(target); err != nil {
+				return err
+			}
+			dir = filepath.Dir(target)
+		}
+		dirs, err := t.missingDirs(dir)
+		if err != nil {
+			return err
+		}
+		for _, d := range dirs {
+			if !seen[d] {
+				seen[d] = true
+				missing = append(missing, d)
+			}
+		}
+		return nil
+	})
+	return missing, err
+}
+
+// missingDirs returns path and its parents that do not exist yet, parents
+// first.
+func (t *Transaction) missingDirs(path string) ([]string, error) {
+	missing := []string{}
+	for dir := filepath.Clean(path); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		exists, err := afero.DirExists(t.GetAferoFs(), dir)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			break
+		}
+		missing = append([]string{dir}, missing...)
+	}
+	return missing, nil
+}
+
+// Restore writes back the original files, removes the files that did not
+// exist and then the created directories that were left empty.
+func (s Snapshot) Restore(fs afero.Fs) error {
+	for i := len(s.Files) - 1; i >= 0; i-- {
+		file := s.Files[i]
+		if file.Existed {
+			if err := fs.MkdirAll(filepath.Dir(file.Path), os.ModePerm); err != nil {
+				return err
+			}
+			if err := afero.WriteFile(fs, file.Path, []byte(file.Content), os.ModePerm); err != nil {
+				return err
+			}
+			if file.Mode != 0 {
+				if err := fs.Chmod(file.Path, file.Mode); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := fs.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	// remove the deepest directories first
+	dirs := append([]string{}, s.Dirs...)
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(filepath.Separator)) > strings.Count(dirs[j], string(filepath.Separator))
+	})
+	for _, dir := range dirs {
+		empty, err := afero.IsEmpty(fs, dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if empty {
+			if err := fs.Remove(dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
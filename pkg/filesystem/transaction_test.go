@@ -0,0 +1,91 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestTransaction_Rollback(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, "existing.txt", []byte("original"), 0644)
+	afero.WriteFile(memFs, "kept/file.txt", []byte("kept"), 0644)
+
+	tx := NewTransaction(NewFileSystem(memFs))
+	if err := tx.WriteFile("existing.txt", "changed"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := tx.WriteFile("existing.txt", "changed twice"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := tx.CreateDirectoryIfNotExists("new/nested"); err != nil {
+		t.Fatalf("CreateDirectoryIfNotExists() error = %v", err)
+	}
+	file, err := tx.CreateFile("new/nested/created.txt")
+	if err != nil {
+		t.Fatalf("CreateFile() error = %v", err)
+	}
+	file.Close()
+	if err := tx.WriteFile("kept/new.txt", "new"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	snapshot := tx.Snapshot()
+	if len(snapshot.Files) != 3 || len(snapshot.Dirs) != 2 {
+		t.Fatalf("Snapshot() = %+v, want 3 files and 2 directories", snapshot)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	content, _ := afero.ReadFile(memFs, "existing.txt")
+	if string(content) != "original" {
+		t.Errorf("existing.txt = %q, want %q", content, "original")
+	}
+	for _, path := range []string{"new/nested/created.txt", "new/nested", "new", "kept/new.txt"} {
+		if exists, _ := afero.Exists(memFs, path); exists {
+			t.Errorf("%s should have been removed", path)
+		}
+	}
+	if exists, _ := afero.Exists(memFs, "kept/file.txt"); !exists {
+		t.Errorf("kept/file.txt should not have been removed")
+	}
+}
+
+func TestTransaction_RollbackFileOperations(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	afero.WriteFile(memFs, "static/logo.png", []byte("logo"), 0644)
+	afero.WriteFile(memFs, "placeholder.txt", []byte("placeholder"), 0644)
+	afero.WriteFile(memFs, "setup.sh", []byte("#!/bin/sh"), 0644)
+	afero.WriteFile(memFs, "main.tmpl", []byte("main"), 0644)
+
+	tx := NewTransaction(NewFileSystem(memFs))
+	steps := []func() error{
+		func() error { return tx.CopyFile("static", "public/static") },
+		func() error { return tx.MoveFile("main.tmpl", "cmd/main.go") },
+		func() error { return tx.DeleteFile("placeholder.txt") },
+		func() error { return tx.Chmod("setup.sh", 0755) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("file operation error = %v", err)
+		}
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	for path, want := range map[string]string{"placeholder.txt": "placeholder", "main.tmpl": "main", "static/logo.png": "logo"} {
+		if content, _ := afero.ReadFile(memFs, path); string(content) != want {
+			t.Errorf("%s = %q, want %q", path, content, want)
+		}
+	}
+	for _, path := range []string{"public", "cmd"} {
+		if exists, _ := afero.Exists(memFs, path); exists {
+			t.Errorf("%s should have been removed", path)
+		}
+	}
+	if info, _ := memFs.Stat("setup.sh"); info.Mode().Perm() != 0644 {
+		t.Errorf("setup.sh mode = %o, want 644", info.Mode().Perm())
+	}
+}